@@ -0,0 +1,247 @@
+package concretes
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-mango/mango/contracts"
+)
+
+// bindSources are struct tags read by binder, in order of precedence.
+var bindSources = []string{"arg", "query", "form", "header"}
+
+var binders sync.Map
+
+type bindField struct {
+	index    []int
+	name     string
+	source   string
+	key      string
+	required bool
+}
+
+// binder is binding plan of an input struct, it is built once per type.
+type binder struct {
+	fields []bindField
+}
+
+func binderOf(t reflect.Type) *binder {
+	if b, ok := binders.Load(t); ok {
+		return b.(*binder)
+	}
+
+	b := &binder{}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		field := bindField{
+			index:    f.Index,
+			name:     f.Name,
+			required: hasTagOption(f.Tag.Get("validate"), "required"),
+		}
+
+		for _, source := range bindSources {
+			if key := f.Tag.Get(source); key != "" && key != "-" {
+				if !isBindable(f.Type) {
+					panic("unsupported type of input field " + t.Name() + "." + f.Name + ".")
+				}
+
+				field.source = source
+				field.key = key
+				break
+			}
+		}
+
+		if field.source == "" {
+			field.key = strings.Split(f.Tag.Get("json"), ",")[0]
+		}
+
+		if field.source != "" || field.required {
+			b.fields = append(b.fields, field)
+		}
+	}
+
+	binders.Store(t, b)
+
+	return b
+}
+
+func hasTagOption(tag, option string) bool {
+	for _, o := range strings.Split(tag, ",") {
+		if strings.TrimSpace(o) == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isBindable(t reflect.Type) bool {
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+// bind fills v from JSON body first, then from tagged sources.
+func (b *binder) bind(ctx contracts.Context, v reflect.Value) error {
+	r := ctx.Request()
+
+	if r.Parent().Body != nil && r.Parent().ContentLength != 0 &&
+		strings.Contains(r.Header().Get("Content-Type"), "json") {
+		if err := r.JSON(v.Addr().Interface()); err != nil {
+			return &contracts.HTTPError{Code: http.StatusBadRequest, Message: "malformed JSON body", Err: err}
+		}
+	}
+
+	invalid := map[string]string{}
+
+	for _, f := range b.fields {
+		values := lookup(r, f.source, f.key)
+		if len(values) == 0 {
+			continue
+		}
+
+		if err := assign(v.FieldByIndex(f.index), values); err != nil {
+			invalid[f.key] = "invalid value " + strconv.Quote(values[0])
+		}
+	}
+
+	if len(invalid) > 0 {
+		return &contracts.HTTPError{Code: http.StatusBadRequest, Message: "invalid parameters", Details: invalid}
+	}
+
+	return nil
+}
+
+// validate checks required fields and calls Validate of input if any.
+func (b *binder) validate(in reflect.Value) error {
+	missing := map[string]string{}
+
+	for _, f := range b.fields {
+		if f.required && isZero(in.Elem().FieldByIndex(f.index)) {
+			key := f.key
+			if key == "" || key == "-" {
+				key = f.name
+			}
+
+			missing[key] = "required"
+		}
+	}
+
+	if len(missing) > 0 {
+		return &contracts.HTTPError{Code: http.StatusUnprocessableEntity, Message: "validation failed", Details: missing}
+	}
+
+	if v, ok := in.Interface().(contracts.Validatable); ok {
+		if err := v.Validate(); err != nil {
+			if he, ok := err.(*contracts.HTTPError); ok {
+				return he
+			}
+
+			return &contracts.HTTPError{Code: http.StatusUnprocessableEntity, Message: err.Error(), Err: err}
+		}
+	}
+
+	return nil
+}
+
+func isZero(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+
+	return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+}
+
+func lookup(r contracts.Request, source, key string) []string {
+	switch source {
+	case "arg":
+		if v, ok := r.Args()[key]; ok {
+			return []string{v}
+		}
+	case "query":
+		return r.URL().Query()[key]
+	case "form":
+		if err := r.Parent().ParseMultipartForm(32 << 20); err != nil && err != http.ErrNotMultipart {
+			return nil
+		}
+
+		return r.Parent().PostForm[key]
+	case "header":
+		return r.Header()[http.CanonicalHeaderKey(key)]
+	}
+
+	return nil
+}
+
+func assign(field reflect.Value, values []string) error {
+	if field.Kind() == reflect.Slice {
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, s := range values {
+			if err := assignOne(slice.Index(i), s); err != nil {
+				return err
+			}
+		}
+
+		field.Set(slice)
+
+		return nil
+	}
+
+	return assignOne(field, values[0])
+}
+
+func assignOne(field reflect.Value, s string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		field.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		field.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		field.SetFloat(f)
+	}
+
+	return nil
+}
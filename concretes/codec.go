@@ -0,0 +1,179 @@
+package concretes
+
+import (
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-mango/mango/contracts"
+)
+
+type codecEntry struct {
+	mediaType string
+	codec     contracts.Codec
+}
+
+var codecs = struct {
+	entries []codecEntry
+	mutex   sync.RWMutex
+}{}
+
+func init() {
+	RegisterCodec("application/json", jsonCodec{})
+	RegisterCodec("application/xml", xmlCodec{"application/xml; charset=utf-8"})
+	RegisterCodec("application/x-yaml", yamlCodec{"application/x-yaml; charset=utf-8"})
+	RegisterCodec("application/yaml", yamlCodec{"application/yaml; charset=utf-8"})
+	RegisterCodec("application/msgpack", msgpackCodec{"application/msgpack"})
+	RegisterCodec("application/x-msgpack", msgpackCodec{"application/x-msgpack"})
+	RegisterCodec("text/plain", textCodec{})
+	RegisterCodec("text/csv", csvCodec{})
+	RegisterCodec("text/xml", xmlCodec{"text/xml; charset=utf-8"})
+	RegisterCodec("text/yaml", yamlCodec{"text/yaml; charset=utf-8"})
+}
+
+// RegisterCodec registers codec for given media type, a registered
+// media type is replaced. Codecs registered earlier win on wildcards.
+func RegisterCodec(mediaType string, c contracts.Codec) {
+	mediaType = strings.ToLower(mediaType)
+
+	codecs.mutex.Lock()
+	defer codecs.mutex.Unlock()
+
+	for i, e := range codecs.entries {
+		if e.mediaType == mediaType {
+			codecs.entries[i].codec = c
+			return
+		}
+	}
+
+	codecs.entries = append(codecs.entries, codecEntry{mediaType, c})
+}
+
+// CodecFor returns codec registered for media type, parameters are ignored.
+func CodecFor(contentType string) (contracts.Codec, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+
+	codecs.mutex.RLock()
+	defer codecs.mutex.RUnlock()
+
+	for _, e := range codecs.entries {
+		if e.mediaType == mediaType {
+			return e.codec, true
+		}
+	}
+
+	return nil, false
+}
+
+type mediaRange struct {
+	mediaType string
+	q         float64
+}
+
+// parseAccept parses Accept header into media ranges ordered by preference.
+func parseAccept(accept string) []mediaRange {
+	ranges := []mediaRange{}
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+
+		if q > 0 {
+			ranges = append(ranges, mediaRange{mediaType, q})
+		}
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		if ranges[i].q != ranges[j].q {
+			return ranges[i].q > ranges[j].q
+		}
+
+		return specificity(ranges[i].mediaType) > specificity(ranges[j].mediaType)
+	})
+
+	return ranges
+}
+
+func specificity(mediaType string) int {
+	switch {
+	case mediaType == "*/*":
+		return 0
+	case strings.HasSuffix(mediaType, "/*"):
+		return 1
+	}
+
+	return 2
+}
+
+func matchMediaRange(pattern, mediaType string) bool {
+	if pattern == "*/*" || pattern == mediaType {
+		return true
+	}
+
+	return strings.HasSuffix(pattern, "/*") && strings.HasPrefix(mediaType, pattern[:len(pattern)-1])
+}
+
+// Negotiate picks codec that best satisfies Accept header,
+// JSON is used when Accept is absent.
+func Negotiate(accept string) (contracts.Codec, error) {
+	candidates, err := negotiateCodecs(accept)
+	if err != nil {
+		return nil, err
+	}
+
+	return candidates[0], nil
+}
+
+// negotiateCodecs returns all codecs acceptable by Accept header in
+// order of preference, so that values one codec can not represent
+// fall back to the next.
+func negotiateCodecs(accept string) ([]contracts.Codec, error) {
+	if strings.TrimSpace(accept) == "" {
+		accept = "application/json"
+	}
+
+	codecs.mutex.RLock()
+	defer codecs.mutex.RUnlock()
+
+	candidates := []contracts.Codec{}
+	picked := map[string]bool{}
+
+	for _, r := range parseAccept(accept) {
+		for _, e := range codecs.entries {
+			if !picked[e.mediaType] && matchMediaRange(r.mediaType, e.mediaType) {
+				picked[e.mediaType] = true
+				candidates = append(candidates, e.codec)
+			}
+		}
+	}
+
+	if len(candidates) > 0 {
+		return candidates, nil
+	}
+
+	available := make([]string, 0, len(codecs.entries))
+	for _, e := range codecs.entries {
+		available = append(available, e.mediaType)
+	}
+
+	return nil, &contracts.HTTPError{
+		Code:    http.StatusNotAcceptable,
+		Message: "none of acceptable media types is supported",
+		Details: available,
+	}
+}
@@ -0,0 +1,115 @@
+package concretes
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+type csvCodec struct{}
+
+func (csvCodec) ContentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+// Encode writes [][]string as is, other lists are normalized through
+// their JSON form so that json tags name the columns.
+func (csvCodec) Encode(w io.Writer, v interface{}) error {
+	cw := csv.NewWriter(w)
+
+	if rows, ok := v.([][]string); ok {
+		cw.WriteAll(rows)
+		return cw.Error()
+	}
+
+	generic, err := normalize(v)
+	if err != nil {
+		return err
+	}
+
+	list, ok := generic.([]interface{})
+	if !ok {
+		return unencodable("CSV", errors.New("only lists are supported"))
+	}
+
+	header := []string{}
+	seen := map[string]bool{}
+	for _, item := range list {
+		if row, ok := item.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(row))
+			for k := range row {
+				if !seen[k] {
+					seen[k] = true
+					keys = append(keys, k)
+				}
+			}
+
+			sort.Strings(keys)
+			header = append(header, keys...)
+		}
+	}
+
+	if len(header) > 0 {
+		cw.Write(header)
+	}
+
+	for _, item := range list {
+		record := []string{}
+
+		switch item := item.(type) {
+		case map[string]interface{}:
+			for _, k := range header {
+				record = append(record, csvCell(item[k]))
+			}
+		case []interface{}:
+			for _, cell := range item {
+				record = append(record, csvCell(cell))
+			}
+		default:
+			record = append(record, csvCell(item))
+		}
+
+		cw.Write(record)
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+func csvCell(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number, bool:
+		return fmt.Sprint(v)
+	}
+
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// normalize converts v to generic form of nil, bool, json.Number, string,
+// []interface{} and map[string]interface{} through encoding/json.
+func normalize(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic interface{}
+
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	return generic, nil
+}
@@ -0,0 +1,139 @@
+package concretes
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math"
+	"sort"
+)
+
+type msgpackCodec struct {
+	contentType string
+}
+
+func (c msgpackCodec) ContentType() string {
+	return c.contentType
+}
+
+// Encode writes MessagePack of v, values are normalized through their
+// JSON form so that json tags and json.Marshaler are respected.
+func (c msgpackCodec) Encode(w io.Writer, v interface{}) error {
+	generic, err := normalize(v)
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, 0, 512)
+	buf = appendMsgpack(buf, generic)
+
+	_, err = w.Write(buf)
+	return err
+}
+
+func appendMsgpack(b []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case nil:
+		return append(b, 0xc0)
+	case bool:
+		if v {
+			return append(b, 0xc3)
+		}
+
+		return append(b, 0xc2)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return appendMsgpackInt(b, i)
+		}
+
+		f, _ := v.Float64()
+		b = append(b, 0xcb)
+		return appendUint64(b, math.Float64bits(f))
+	case string:
+		n := len(v)
+		switch {
+		case n < 32:
+			b = append(b, 0xa0|byte(n))
+		case n <= math.MaxUint8:
+			b = append(b, 0xd9, byte(n))
+		case n <= math.MaxUint16:
+			b = append(b, 0xda)
+			b = appendUint16(b, uint16(n))
+		default:
+			b = append(b, 0xdb)
+			b = appendUint32(b, uint32(n))
+		}
+
+		return append(b, v...)
+	case []interface{}:
+		b = appendMsgpackHeader(b, len(v), 0x90, 0xdc, 0xdd)
+		for _, item := range v {
+			b = appendMsgpack(b, item)
+		}
+
+		return b
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		b = appendMsgpackHeader(b, len(v), 0x80, 0xde, 0xdf)
+		for _, k := range keys {
+			b = appendMsgpack(b, k)
+			b = appendMsgpack(b, v[k])
+		}
+
+		return b
+	}
+
+	return append(b, 0xc0)
+}
+
+func appendMsgpackHeader(b []byte, n int, fix, b16, b32 byte) []byte {
+	switch {
+	case n < 16:
+		return append(b, fix|byte(n))
+	case n <= math.MaxUint16:
+		return appendUint16(append(b, b16), uint16(n))
+	}
+
+	return appendUint32(append(b, b32), uint32(n))
+}
+
+func appendMsgpackInt(b []byte, i int64) []byte {
+	switch {
+	case i >= 0 && i <= 0x7f:
+		return append(b, byte(i))
+	case i < 0 && i >= -32:
+		return append(b, byte(int8(i)))
+	case i >= math.MinInt8 && i <= math.MaxInt8:
+		return append(b, 0xd0, byte(int8(i)))
+	case i >= math.MinInt16 && i <= math.MaxInt16:
+		return appendUint16(append(b, 0xd1), uint16(int16(i)))
+	case i >= math.MinInt32 && i <= math.MaxInt32:
+		return appendUint32(append(b, 0xd2), uint32(int32(i)))
+	}
+
+	return appendUint64(append(b, 0xd3), uint64(i))
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	var tmp [2]byte
+	binary.BigEndian.PutUint16(tmp[:], v)
+	return append(b, tmp[:]...)
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var tmp [4]byte
+	binary.BigEndian.PutUint32(tmp[:], v)
+	return append(b, tmp[:]...)
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	var tmp [8]byte
+	binary.BigEndian.PutUint64(tmp[:], v)
+	return append(b, tmp[:]...)
+}
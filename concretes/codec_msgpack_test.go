@@ -0,0 +1,252 @@
+package concretes
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func encodeMsgpack(t *testing.T, v interface{}) []byte {
+	buf := &bytes.Buffer{}
+	if err := (msgpackCodec{"application/msgpack"}).Encode(buf, v); err != nil {
+		t.Fatalf("Encode(%v): %v", v, err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestMsgpackEncode(t *testing.T) {
+	for _, tt := range []struct {
+		v    interface{}
+		want []byte
+	}{
+		{nil, []byte{0xc0}},
+		{true, []byte{0xc3}},
+		{false, []byte{0xc2}},
+		{0, []byte{0x00}},
+		{127, []byte{0x7f}},
+		{128, []byte{0xd1, 0x00, 0x80}},
+		{-1, []byte{0xff}},
+		{-32, []byte{0xe0}},
+		{-33, []byte{0xd0, 0xdf}},
+		{-128, []byte{0xd0, 0x80}},
+		{-129, []byte{0xd1, 0xff, 0x7f}},
+		{65536, []byte{0xd2, 0x00, 0x01, 0x00, 0x00}},
+		{int64(math.MaxInt64), []byte{0xd3, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{1.5, []byte{0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0}},
+		{"", []byte{0xa0}},
+		{"abc", []byte{0xa3, 'a', 'b', 'c'}},
+		{strings.Repeat("x", 32), append([]byte{0xd9, 32}, strings.Repeat("x", 32)...)},
+		{strings.Repeat("x", 256), append([]byte{0xda, 0x01, 0x00}, strings.Repeat("x", 256)...)},
+		{[]int{}, []byte{0x90}},
+		{[]int{1, 2}, []byte{0x92, 0x01, 0x02}},
+		{map[string]int{"b": 2, "a": 1}, []byte{0x82, 0xa1, 'a', 0x01, 0xa1, 'b', 0x02}},
+		{struct {
+			ID   int    `json:"id"`
+			Skip string `json:"-"`
+		}{7, "x"}, []byte{0x81, 0xa2, 'i', 'd', 0x07}},
+	} {
+		if got := encodeMsgpack(t, tt.v); !bytes.Equal(got, tt.want) {
+			t.Errorf("Encode(%v) = % x, want % x", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestMsgpackRoundTrip(t *testing.T) {
+	long := make([]interface{}, 70000)
+	for i := range long {
+		long[i] = int64(i % 3)
+	}
+
+	wide := map[string]interface{}{}
+	for i := 0; i < 20; i++ {
+		wide[strings.Repeat("k", i+1)] = int64(i)
+	}
+
+	for _, v := range []interface{}{
+		map[string]interface{}{
+			"name":   "mango",
+			"tags":   []interface{}{"a", "b"},
+			"nested": map[string]interface{}{"ok": true, "none": nil},
+			"float":  -2.25,
+			"int":    int64(-70000),
+		},
+		long,
+		wide,
+		strings.Repeat("y", 70000),
+	} {
+		got, rest, err := decodeMsgpack(encodeMsgpack(t, v))
+		if err != nil || len(rest) > 0 {
+			t.Fatalf("decode: %v, %d bytes left", err, len(rest))
+		}
+
+		if !reflect.DeepEqual(got, v) {
+			t.Errorf("round trip of %.40v = %.40v", v, got)
+		}
+	}
+}
+
+func TestMsgpackUnencodable(t *testing.T) {
+	if err := (msgpackCodec{"application/msgpack"}).Encode(&bytes.Buffer{}, make(chan int)); err == nil {
+		t.Error("channel encoded without error")
+	}
+}
+
+// decodeMsgpack decodes formats produced by msgpackCodec.
+func decodeMsgpack(b []byte) (interface{}, []byte, error) {
+	if len(b) == 0 {
+		return nil, nil, errors.New("unexpected end")
+	}
+
+	c, b := b[0], b[1:]
+
+	take := func(n int) ([]byte, error) {
+		if len(b) < n {
+			return nil, errors.New("unexpected end")
+		}
+
+		v := b[:n]
+		b = b[n:]
+		return v, nil
+	}
+
+	size := func(n int) (int, error) {
+		v, err := take(n)
+		if err != nil {
+			return 0, err
+		}
+
+		switch n {
+		case 1:
+			return int(v[0]), nil
+		case 2:
+			return int(binary.BigEndian.Uint16(v)), nil
+		}
+
+		return int(binary.BigEndian.Uint32(v)), nil
+	}
+
+	var (
+		n   int
+		err error
+	)
+
+	switch {
+	case c <= 0x7f:
+		return int64(c), b, nil
+	case c >= 0xe0:
+		return int64(int8(c)), b, nil
+	case c == 0xc0:
+		return nil, b, nil
+	case c == 0xc2, c == 0xc3:
+		return c == 0xc3, b, nil
+	case c == 0xd0:
+		v, err := take(1)
+		if err != nil {
+			return nil, nil, err
+		}
+		return int64(int8(v[0])), b, nil
+	case c == 0xd1:
+		v, err := take(2)
+		if err != nil {
+			return nil, nil, err
+		}
+		return int64(int16(binary.BigEndian.Uint16(v))), b, nil
+	case c == 0xd2:
+		v, err := take(4)
+		if err != nil {
+			return nil, nil, err
+		}
+		return int64(int32(binary.BigEndian.Uint32(v))), b, nil
+	case c == 0xd3, c == 0xcb:
+		v, err := take(8)
+		if err != nil {
+			return nil, nil, err
+		}
+		if c == 0xcb {
+			return math.Float64frombits(binary.BigEndian.Uint64(v)), b, nil
+		}
+		return int64(binary.BigEndian.Uint64(v)), b, nil
+	case c&0xe0 == 0xa0, c == 0xd9, c == 0xda, c == 0xdb:
+		switch c {
+		case 0xd9:
+			n, err = size(1)
+		case 0xda:
+			n, err = size(2)
+		case 0xdb:
+			n, err = size(4)
+		default:
+			n = int(c & 0x1f)
+		}
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		v, err := take(n)
+		if err != nil {
+			return nil, nil, err
+		}
+		return string(v), b, nil
+	case c&0xf0 == 0x90, c == 0xdc, c == 0xdd:
+		switch c {
+		case 0xdc:
+			n, err = size(2)
+		case 0xdd:
+			n, err = size(4)
+		default:
+			n = int(c & 0x0f)
+		}
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], b, err = decodeMsgpack(b); err != nil {
+				return nil, nil, err
+			}
+		}
+		return items, b, nil
+	case c&0xf0 == 0x80, c == 0xde, c == 0xdf:
+		switch c {
+		case 0xde:
+			n, err = size(2)
+		case 0xdf:
+			n, err = size(4)
+		default:
+			n = int(c & 0x0f)
+		}
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		m := map[string]interface{}{}
+		for i := 0; i < n; i++ {
+			var k, v interface{}
+			if k, b, err = decodeMsgpack(b); err != nil {
+				return nil, nil, err
+			}
+
+			key, ok := k.(string)
+			if !ok {
+				return nil, nil, errors.New("non-string key")
+			}
+
+			if v, b, err = decodeMsgpack(b); err != nil {
+				return nil, nil, err
+			}
+
+			m[key] = v
+		}
+		return m, b, nil
+	}
+
+	return nil, nil, errors.New("unknown format")
+}
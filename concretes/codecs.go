@@ -0,0 +1,106 @@
+package concretes
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+
+	"github.com/go-mango/mango/contracts"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// unencodable reports value that the negotiated codec can not represent.
+func unencodable(mediaType string, err error) error {
+	return &contracts.HTTPError{
+		Code:    http.StatusNotAcceptable,
+		Message: "value can not be encoded as " + mediaType,
+		Err:     err,
+	}
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) ContentType() string {
+	return "application/json; charset=utf-8"
+}
+
+func (jsonCodec) Encode(w io.Writer, v interface{}) error {
+	if r, ok := w.(contracts.Response); ok {
+		return r.WriteJSON(v)
+	}
+
+	return json.NewEncoder(w).Encode(v)
+}
+
+type xmlCodec struct {
+	contentType string
+}
+
+func (c xmlCodec) ContentType() string {
+	return c.contentType
+}
+
+// Encode writes v as XML document, slices and maps have no root
+// element to become one and are reported unencodable.
+func (c xmlCodec) Encode(w io.Writer, v interface{}) error {
+	switch reflect.Indirect(reflect.ValueOf(v)).Kind() {
+	case reflect.Invalid, reflect.Slice, reflect.Array, reflect.Map:
+		return unencodable("XML", fmt.Errorf("%T has no root element", v))
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	err := xml.NewEncoder(w).Encode(v)
+	if _, ok := err.(*xml.UnsupportedTypeError); ok {
+		return unencodable("XML", err)
+	}
+
+	return err
+}
+
+type yamlCodec struct {
+	contentType string
+}
+
+func (c yamlCodec) ContentType() string {
+	return c.contentType
+}
+
+func (c yamlCodec) Encode(w io.Writer, v interface{}) error {
+	e := yaml.NewEncoder(w)
+	if err := e.Encode(v); err != nil {
+		return err
+	}
+
+	return e.Close()
+}
+
+type textCodec struct{}
+
+func (textCodec) ContentType() string {
+	return "text/plain; charset=utf-8"
+}
+
+// Encode writes strings, fmt.Stringer, numbers and bools as text,
+// composite values are reported unencodable.
+func (textCodec) Encode(w io.Writer, v interface{}) error {
+	if _, ok := v.(fmt.Stringer); !ok {
+		switch reflect.ValueOf(v).Kind() {
+		case reflect.String, reflect.Bool,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+		default:
+			return unencodable("text", fmt.Errorf("%T is not a text value", v))
+		}
+	}
+
+	_, err := fmt.Fprint(w, v)
+	return err
+}
@@ -0,0 +1,58 @@
+package concretes
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-mango/mango/contracts"
+)
+
+type codecPoint struct {
+	X int
+	Y string
+}
+
+func TestCodecsUnencodable(t *testing.T) {
+	xml := xmlCodec{"application/xml; charset=utf-8"}
+	text := textCodec{}
+
+	for _, tt := range []struct {
+		codec contracts.Codec
+		v     interface{}
+		want  string
+	}{
+		{xml, codecPoint{1, "x"}, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<codecPoint><X>1</X><Y>x</Y></codecPoint>"},
+		{xml, &codecPoint{1, "x"}, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<codecPoint><X>1</X><Y>x</Y></codecPoint>"},
+		{xml, []codecPoint{{1, "x"}, {2, "y"}}, ""},
+		{xml, [2]int{1, 2}, ""},
+		{xml, map[string]int{"a": 1}, ""},
+		{xml, nil, ""},
+		{text, "hello", "hello"},
+		{text, 42, "42"},
+		{text, 1.5, "1.5"},
+		{text, true, "true"},
+		{text, time.Second, "1s"},
+		{text, codecPoint{1, "x"}, ""},
+		{text, []int{1}, ""},
+		{text, map[string]int{"a": 1}, ""},
+		{text, nil, ""},
+	} {
+		buf := &bytes.Buffer{}
+		err := tt.codec.Encode(buf, tt.v)
+
+		if tt.want == "" {
+			he, ok := err.(*contracts.HTTPError)
+			if !ok || he.Code != http.StatusNotAcceptable {
+				t.Errorf("%T.Encode(%#v) = %q, %v, want 406", tt.codec, tt.v, buf.String(), err)
+			}
+
+			continue
+		}
+
+		if err != nil || buf.String() != tt.want {
+			t.Errorf("%T.Encode(%#v) = %q, %v, want %q", tt.codec, tt.v, buf.String(), err, tt.want)
+		}
+	}
+}
@@ -1,35 +1,64 @@
 package concretes
 
 import (
+	stdcontext "context"
+	"net/http"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/go-mango/logy"
 	"github.com/go-mango/mango/contracts"
 )
 
 type context struct {
-	request  contracts.Request
-	response contracts.Response
-	cache    contracts.Cachable
-	stack    []contracts.ThenableFunc
-	auth     contracts.Authenable
-	session  contracts.Session
+	request      contracts.Request
+	response     contracts.Response
+	services     *Services
+	route        contracts.Route
+	stack        []contracts.ThenableFunc
+	auth         contracts.Authenable
+	session      contracts.Session
+	values       map[string]interface{}
+	std          stdcontext.Context
+	transformers []contracts.Transformer
+	after        []func(contracts.Context)
+	timings      []timing
+	timed        bool
+	stage        int
+	nested       time.Duration
+	aborted      bool
+	mutex        sync.RWMutex
 }
 
 // NewContext create new Context instance
 func NewContext(
 	request contracts.Request,
 	response contracts.Response,
-	cache contracts.Cachable,
+	services *Services,
 	stack []contracts.ThenableFunc,
 	route contracts.Route,
 ) contracts.ThenableContext {
+	bindJSON(request, response, services)
+
 	return &context{
 		request,
 		response,
-		cache,
-		append(stack, handleResponse(route.Callable())),
+		services,
+		route,
+		append(stack[:len(stack):len(stack)], handleResponse(route.Callable())), //never writes into caller's array.
 		newAuth(),
-		NewSession(),
+		nil,
+		map[string]interface{}{},
+		request.Parent().Context(),
+		nil,
+		nil,
+		nil,
+		false,
+		0,
+		0,
+		false,
+		sync.RWMutex{},
 	}
 }
 
@@ -45,13 +74,43 @@ func (c *context) Response() contracts.Response {
 
 // Next executes the next middleware func
 func (c *context) Next() {
-	if len(c.stack) > 0 {
+	if !c.aborted && len(c.stack) > 0 {
 		m := c.stack[0]
 		c.stack = c.stack[1:]
+
+		if c.timed {
+			c.timeStage(m, len(c.stack) == 0)
+			return
+		}
+
 		m(c)
 	}
 }
 
+// Abort stops the remaining middlewares and handler from being executed.
+func (c *context) Abort() {
+	c.aborted = true
+	c.stack = nil
+}
+
+// AbortWithStatus aborts the chain and sets response status.
+func (c *context) AbortWithStatus(code int) {
+	c.Abort()
+	c.response.SetStatus(code)
+}
+
+// AbortWith aborts the chain and renders value the same way
+// as values returned by handlers.
+func (c *context) AbortWith(code int, value interface{}) {
+	c.Abort()
+	render(c, code, value)
+}
+
+// IsAborted reports whether the chain was aborted.
+func (c *context) IsAborted() bool {
+	return c.aborted
+}
+
 // URL generates URL with given params.
 func (c *context) URL(u string, p map[string]string) string {
 	for _, k := range p {
@@ -72,10 +131,337 @@ func (c *context) Auth() contracts.Authenable {
 
 // Cache returns cache storage instance of incoming request.
 func (c *context) Cache() contracts.Cachable {
-	return c.cache
+	return c.services.Cache
+}
+
+// Mode returns mode of application.
+func (c *context) Mode() string {
+	return c.services.Mode
+}
+
+// Router returns application router, it is used to look up named routes.
+func (c *context) Router() contracts.Router {
+	return c.services.Router
 }
 
-// Session returns session storage instace of incoming request.
+// Route returns route matched by incoming request.
+func (c *context) Route() contracts.Route {
+	return c.route
+}
+
+// View creates view rendered by application view engine.
+func (c *context) View(name string, data interface{}) contracts.Renderable {
+	return &View{Name: name, Data: data}
+}
+
+// Session returns session started by Session middleware,
+// it is nil when the middleware is not in use.
 func (c *context) Session() contracts.Session {
 	return c.session
 }
+
+// SetSession attaches session to incoming request.
+func (c *context) SetSession(s contracts.Session) {
+	c.session = s
+}
+
+// Set stores value with given key for the lifetime of incoming request,
+// it is the way for middlewares to hand data to handlers.
+func (c *context) Set(key string, value interface{}) {
+	c.mutex.Lock()
+	c.values[key] = value
+	c.mutex.Unlock()
+}
+
+// Get retrieves value stored by Set.
+func (c *context) Get(key string) (value interface{}, ok bool) {
+	c.mutex.RLock()
+	value, ok = c.values[key]
+	c.mutex.RUnlock()
+	return
+}
+
+// MustGet retrieves value stored by Set, it panics if key does not exist.
+func (c *context) MustGet(key string) interface{} {
+	if value, ok := c.Get(key); ok {
+		return value
+	}
+
+	panic("key \"" + key + "\" does not exist in context.")
+}
+
+// GetString retrieves value stored by Set as string.
+func (c *context) GetString(key string) (s string) {
+	if value, ok := c.Get(key); ok {
+		s, _ = value.(string)
+	}
+
+	return
+}
+
+// GetInt retrieves value stored by Set as int.
+func (c *context) GetInt(key string) (i int) {
+	if value, ok := c.Get(key); ok {
+		i, _ = value.(int)
+	}
+
+	return
+}
+
+// GetInt64 retrieves value stored by Set as int64.
+func (c *context) GetInt64(key string) (i int64) {
+	if value, ok := c.Get(key); ok {
+		i, _ = value.(int64)
+	}
+
+	return
+}
+
+// GetFloat64 retrieves value stored by Set as float64.
+func (c *context) GetFloat64(key string) (f float64) {
+	if value, ok := c.Get(key); ok {
+		f, _ = value.(float64)
+	}
+
+	return
+}
+
+// GetBool retrieves value stored by Set as bool.
+func (c *context) GetBool(key string) (b bool) {
+	if value, ok := c.Get(key); ok {
+		b, _ = value.(bool)
+	}
+
+	return
+}
+
+// RequestID returns correlation ID of incoming request.
+func (c *context) RequestID() string {
+	return c.request.ID()
+}
+
+// Error hands err to the application error handler.
+func (c *context) Error(err error) {
+	c.services.OnError(c, err)
+}
+
+// Transform registers transformer applied to encoded return value,
+// transformers run in order of registration.
+func (c *context) Transform(fn contracts.Transformer) {
+	c.transformers = append(c.transformers, fn)
+}
+
+// transform applies transformers registered on ctx.
+func transform(ctx contracts.Context, code int, v interface{}) (int, interface{}, error) {
+	c, ok := ctx.(*context)
+	if !ok {
+		return code, v, nil
+	}
+
+	for _, fn := range c.transformers {
+		var err error
+		if code, v, err = fn(ctx, code, v); err != nil {
+			return code, nil, err
+		}
+	}
+
+	return code, v, nil
+}
+
+// OnAfterResponse registers fn called in background once response is
+// sent to client, it suits audit logging and cache warming. fn gets a
+// detached copy of ctx: its Response is a read-only snapshot of status,
+// size and headers, request headers, URL and args are readable while
+// the request body is not, and ctx.Std() keeps values but is never
+// canceled.
+func (c *context) OnAfterResponse(fn func(contracts.Context)) {
+	c.after = append(c.after, fn)
+}
+
+// RunAfterResponse hands hooks registered by OnAfterResponse to the
+// worker pool of application, it must be called after response is sent.
+func RunAfterResponse(ctx contracts.Context) {
+	c, ok := ctx.(*context)
+	if !ok || len(c.after) == 0 {
+		return
+	}
+
+	after := c.after
+	c.after = nil
+
+	detached := c.detached()
+
+	task := func() {
+		for _, fn := range after {
+			safeRun(func() { fn(detached) })
+		}
+	}
+
+	if c.services.Workers == nil {
+		go safeRun(task)
+		return
+	}
+
+	if !c.services.Workers.Submit(task) {
+		logy.Std().Warnf("AFTER RESPONSE: [%s] worker queue is full, hooks are dropped", c.RequestID())
+	}
+}
+
+// detached copies context for use after ServeHTTP returned, nothing in
+// the copy reaches http.ResponseWriter.
+func (c *context) detached() *context {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return &context{
+		c.request,
+		snapshotResponse(c.response),
+		c.services,
+		c.route,
+		nil,
+		c.auth,
+		c.session,
+		c.values,
+		detachedStd{c.std},
+		nil,
+		nil,
+		c.timings,
+		c.timed,
+		c.stage,
+		c.nested,
+		true,
+		sync.RWMutex{},
+	}
+}
+
+// detachedStd keeps values of a standard context without its deadline
+// and cancellation.
+type detachedStd struct {
+	stdcontext.Context
+}
+
+func (detachedStd) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedStd) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedStd) Err() error {
+	return nil
+}
+
+// Timing records duration of a named step, e.g. a database query.
+// Metrics are sent in Server-Timing header when ServerTiming
+// middleware is in use.
+func (c *context) Timing(name string, d time.Duration) {
+	c.record(timing{name, "", d})
+}
+
+// SSE holds the connection open as a Server-Sent Events stream.
+//
+/*
+	return ctx.SSE(func(stream contracts.EventStream) error {
+		defer hub.Subscribe("dashboard", stream)()
+		<-stream.Done()
+		return nil
+	})
+*/
+func (c *context) SSE(fn func(contracts.EventStream) error) (int, interface{}) {
+	return serveEvents(c, fn)
+}
+
+// CheckPreconditions evaluates conditional headers against current
+// version of the resource, returned error is ready to be returned by
+// handlers, ErrNotModified included. Set required to reject unconditional
+// writes with 428. Writes set the new version themselves.
+//
+/*
+	if err := ctx.CheckPreconditions(contracts.Version{ETag: doc.Rev}, true); err != nil {
+		return 0, err
+	}
+
+	doc = save(doc)
+	ctx.SetVersion(contracts.Version{ETag: doc.Rev})
+*/
+func (c *context) CheckPreconditions(current contracts.Version, required bool) error {
+	return CheckPreconditions(c, current, required)
+}
+
+// SetVersion sets ETag and Last-Modified of the resource to response,
+// writes call it with the new version after CheckPreconditions passed.
+func (c *context) SetVersion(v contracts.Version) {
+	SetVersion(c, v)
+}
+
+// Std returns standard context.Context bound to incoming request,
+// it is canceled when client disconnects or server shuts down.
+func (c *context) Std() stdcontext.Context {
+	return c.std
+}
+
+// SetStd replaces standard context.Context of incoming request.
+func (c *context) SetStd(std stdcontext.Context) {
+	c.std = std
+}
+
+// WithValue attaches key/value pair to standard context.Context.
+func (c *context) WithValue(key, value interface{}) {
+	c.std = stdcontext.WithValue(c.std, key, value)
+}
+
+// Keyring returns keyring used to protect cookies.
+func (c *context) Keyring() contracts.Keyring {
+	return c.services.Keyring
+}
+
+// SetSignedCookie signs cookie value with HMAC before sending it.
+func (c *context) SetSignedCookie(cookie *http.Cookie) {
+	signed := *cookie
+	signed.Value = c.Keyring().Sign(cookie.Name, cookie.Value)
+	c.response.SetCookie(&signed)
+}
+
+// SignedCookie retrieves value of cookie set by SetSignedCookie.
+func (c *context) SignedCookie(name string) (string, error) {
+	cookie, err := c.request.Parent().Cookie(name)
+	if err != nil {
+		return "", err
+	}
+
+	return c.Keyring().Verify(name, cookie.Value)
+}
+
+// SetEncryptedCookie encrypts cookie value with AES-GCM before sending it.
+func (c *context) SetEncryptedCookie(cookie *http.Cookie) error {
+	sealed, err := c.Keyring().Encrypt(cookie.Name, cookie.Value)
+	if err != nil {
+		return err
+	}
+
+	encrypted := *cookie
+	encrypted.Value = sealed
+	c.response.SetCookie(&encrypted)
+
+	return nil
+}
+
+// EncryptedCookie retrieves value of cookie set by SetEncryptedCookie.
+func (c *context) EncryptedCookie(name string) (string, error) {
+	cookie, err := c.request.Parent().Cookie(name)
+	if err != nil {
+		return "", err
+	}
+
+	return c.Keyring().Decrypt(name, cookie.Value)
+}
+
+// servicesOf returns services of ctx, it is empty for foreign contexts.
+func servicesOf(ctx contracts.Context) *Services {
+	if c, ok := ctx.(*context); ok {
+		return c.services
+	}
+
+	return &Services{}
+}
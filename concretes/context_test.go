@@ -0,0 +1,61 @@
+package concretes
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-mango/mango/contracts"
+)
+
+// newTestContext creates context of a request with given headers
+// routed to a handler that renders nothing.
+func newTestContext(method, target string, header map[string]string) contracts.Context {
+	r := httptest.NewRequest(method, target, nil)
+	for k, v := range header {
+		r.Header.Set(k, v)
+	}
+
+	route := NewRoute(method, r.URL.Path, func(contracts.Context) (int, interface{}) { return 0, nil })
+
+	return NewContext(NewRequest(r), NewResponse(httptest.NewRecorder()), &Services{}, nil, route)
+}
+
+func TestRunAfterResponseDetached(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/doc", nil)
+	route := NewRoute("GET", "/doc", func(contracts.Context) (int, interface{}) { return 0, nil })
+	ctx := NewContext(NewRequest(r), NewResponse(w), &Services{}, nil, route)
+
+	done := make(chan contracts.Context, 1)
+	ctx.OnAfterResponse(func(ctx contracts.Context) {
+		ctx.Response().Header().Set("X-Late", "1")
+		ctx.Response().WriteString("late")
+		done <- ctx
+	})
+
+	ctx.Response().Header().Set("X-Sent", "1")
+	ctx.Response().SetStatus(201)
+	ctx.Response().WriteString("body")
+	ctx.Response().Send()
+
+	RunAfterResponse(ctx)
+
+	select {
+	case hooked := <-done:
+		res := hooked.Response()
+		if res.Status() != 201 || res.Size() != 4 || res.Header().Get("X-Sent") != "1" {
+			t.Errorf("snapshot = %d, %d bytes, headers %v", res.Status(), res.Size(), res.Header())
+		}
+
+		if hooked.Std().Err() != nil {
+			t.Errorf("Std() of detached context is canceled: %v", hooked.Std().Err())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("hook did not run")
+	}
+
+	if w.Body.String() != "body" || w.Header().Get("X-Late") != "" {
+		t.Errorf("hook reached client: body %q, headers %v", w.Body.String(), w.Header())
+	}
+}
@@ -0,0 +1,11 @@
+package concretes
+
+// Envelope wraps rendered values with metadata, e.g. pages of
+// Paginator. Transformers leave values already enveloped alone.
+type Envelope struct {
+	XMLName struct{}    `json:"-" xml:"response" yaml:"-"`
+	Data    interface{} `json:"data,omitempty" xml:"data,omitempty" yaml:"data,omitempty"`
+	Meta    interface{} `json:"meta,omitempty" xml:"meta,omitempty" yaml:"meta,omitempty"`
+	Links   interface{} `json:"links,omitempty" xml:"links,omitempty" yaml:"links,omitempty"`
+	Error   interface{} `json:"error,omitempty" xml:"error,omitempty" yaml:"error,omitempty"`
+}
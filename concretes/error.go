@@ -0,0 +1,108 @@
+package concretes
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-mango/logy"
+	"github.com/go-mango/mango/contracts"
+)
+
+// ProblemContentType is media type of RFC 7807 problem details.
+const ProblemContentType = "application/problem+json"
+
+// Problem is RFC 7807 problem details object.
+type Problem struct {
+	Type      string      `json:"type"`
+	Title     string      `json:"title"`
+	Status    int         `json:"status"`
+	Detail    string      `json:"detail,omitempty"`
+	Instance  string      `json:"instance,omitempty"`
+	RequestID string      `json:"request_id,omitempty"`
+	Details   interface{} `json:"details,omitempty"`
+}
+
+// ToHTTPError converts err to *contracts.HTTPError, code is used
+// when err does not carry its own status code.
+func ToHTTPError(code int, err error) *contracts.HTTPError {
+	var he *contracts.HTTPError
+	if errors.As(err, &he) {
+		if he.Code == 0 {
+			copied := *he
+			copied.Code = http.StatusInternalServerError
+			return &copied
+		}
+
+		return he
+	}
+
+	if code < 400 {
+		code = http.StatusInternalServerError
+	}
+
+	return &contracts.HTTPError{
+		Code:    code,
+		Message: http.StatusText(code),
+		Err:     err,
+	}
+}
+
+// NewProblem creates problem details from err.
+func NewProblem(ctx contracts.Context, err error) *Problem {
+	he := ToHTTPError(http.StatusInternalServerError, err)
+
+	p := &Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(he.Code),
+		Status:    he.Code,
+		Instance:  ctx.Request().URL().Path,
+		RequestID: ctx.RequestID(),
+		Details:   he.Details,
+	}
+
+	if he.Message != p.Title {
+		p.Detail = he.Message
+	}
+
+	return p
+}
+
+// DefaultErrorHandler logs server errors and renders errors as
+// RFC 7807 problem details, browsers get error pages instead.
+func DefaultErrorHandler(ctx contracts.Context, err error) {
+	he := ToHTTPError(http.StatusInternalServerError, err)
+
+	if he.Code >= 500 || ctx.Response().Committed() {
+		var pe *PanicError
+		if errors.As(err, &pe) {
+			logy.Std().Warnf("ERROR: [%s] %s\n%s", ctx.RequestID(), err.Error(), pe.Stack)
+		} else {
+			logy.Std().Warnf("ERROR: [%s] %s", ctx.RequestID(), err.Error())
+		}
+	}
+
+	if ctx.Response().Committed() {
+		return //too late to replace what client has received.
+	}
+
+	ctx.Response().Clear()
+	ctx.Response().SetStatus(he.Code)
+
+	if prefersHTML(ctx.Request().Header().Get("Accept")) {
+		renderErrorPage(ctx, he, err)
+		return
+	}
+
+	ctx.Response().Header().Set("Content-Type", ProblemContentType)
+
+	//transformers may reshape errors too, e.g. wrap them in envelopes.
+	var v interface{} = NewProblem(ctx, he)
+	if code, transformed, err := transform(ctx, he.Code, v); err == nil {
+		ctx.Response().SetStatus(code)
+		v = transformed
+	}
+
+	if e := ctx.Response().WriteJSON(v); e != nil {
+		ctx.Response().Clear()
+	}
+}
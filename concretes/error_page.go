@@ -0,0 +1,191 @@
+package concretes
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"runtime/debug"
+	"sort"
+
+	"github.com/go-mango/logy"
+	"github.com/go-mango/mango/contracts"
+)
+
+// PanicError is error of a recovered panic.
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+// Error returns message of the panic.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// ErrorView creates error renderer that renders template name with
+// application view engine, templates receive *Problem as data.
+//
+/*
+	m.SetErrorRenderer(404, concretes.ErrorView("errors/404"))
+	m.SetErrorRenderer(0, concretes.ErrorView("errors/default"))
+*/
+func ErrorView(name string) contracts.ErrorRenderer {
+	return func(ctx contracts.Context, he *contracts.HTTPError) error {
+		return (&View{Name: name, Data: NewProblem(ctx, he)}).Render(ctx)
+	}
+}
+
+// prefersHTML reports whether client ranks HTML over JSON, API
+// clients sending */* get JSON.
+func prefersHTML(accept string) bool {
+	for _, r := range parseAccept(accept) {
+		switch r.mediaType {
+		case "text/html", "application/xhtml+xml":
+			return true
+		case "*/*", "application/json", ProblemContentType:
+			return false
+		}
+	}
+
+	return false
+}
+
+// renderErrorPage renders error page for browsers, the debug page
+// is shown in development mode instead.
+func renderErrorPage(ctx contracts.Context, he *contracts.HTTPError, err error) {
+	services := servicesOf(ctx)
+
+	ctx.Response().Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if services.Mode == contracts.DevelopmentMode {
+		if e := renderDebugPage(ctx, he, err); e == nil {
+			return
+		}
+
+		ctx.Response().Clear()
+	}
+
+	fn, ok := services.ErrorRenderers[he.Code]
+	if !ok {
+		fn, ok = services.ErrorRenderers[0]
+	}
+
+	if ok {
+		e := fn(ctx, he)
+		if e == nil {
+			return
+		}
+
+		logy.Std().Warnf("ERROR PAGE: [%s] %s", ctx.RequestID(), e.Error())
+
+		ctx.Response().Clear()
+		ctx.Response().SetStatus(he.Code)
+		ctx.Response().Header().Set("Content-Type", "text/html; charset=utf-8")
+	}
+
+	errorPage.Execute(ctx.Response(), NewProblem(ctx, he))
+}
+
+var errorPage = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{ .Status }} {{ .Title }}</title></head>
+<body>
+<h1>{{ .Status }} {{ .Title }}</h1>
+{{ if .Detail }}<p>{{ .Detail }}</p>{{ end }}
+{{ if .RequestID }}<p><small>Request ID: {{ .RequestID }}</small></p>{{ end }}
+</body>
+</html>
+`))
+
+type debugHeader struct {
+	Name   string
+	Values []string
+}
+
+type debugData struct {
+	Problem *Problem
+	Errors  []string
+	Stack   string
+	Method  string
+	URL     string
+	Route   string
+	Args    map[string]string
+	Headers []debugHeader
+}
+
+// renderDebugPage shows everything known about the failed request,
+// it must never be enabled in production.
+func renderDebugPage(ctx contracts.Context, he *contracts.HTTPError, err error) error {
+	data := &debugData{
+		Problem: NewProblem(ctx, he),
+		Method:  ctx.Request().Method(),
+		URL:     ctx.Request().URL().String(),
+		Args:    ctx.Request().Args(),
+	}
+
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		data.Errors = append(data.Errors, fmt.Sprintf("%T: %s", e, e.Error()))
+	}
+
+	var pe *PanicError
+	if errors.As(err, &pe) {
+		data.Stack = string(pe.Stack)
+	} else {
+		data.Stack = string(debug.Stack())
+	}
+
+	if route := ctx.Route(); route != nil {
+		data.Route = route.Method() + " " + route.Path()
+		if route.Name() != "" {
+			data.Route += " (" + route.Name() + ")"
+		}
+	}
+
+	for name, values := range ctx.Request().Header() {
+		data.Headers = append(data.Headers, debugHeader{name, values})
+	}
+
+	sort.Slice(data.Headers, func(i, j int) bool {
+		return data.Headers[i].Name < data.Headers[j].Name
+	})
+
+	return debugPage.Execute(ctx.Response(), data)
+}
+
+var debugPage = template.Must(template.New("debug").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Problem.Status }} {{ .Problem.Title }}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+pre { background: #f6f6f6; padding: 1em; overflow: auto; }
+th { text-align: left; padding-right: 1em; vertical-align: top; }
+</style>
+</head>
+<body>
+<h1>{{ .Problem.Status }} {{ .Problem.Title }}</h1>
+{{ if .Problem.Detail }}<p>{{ .Problem.Detail }}</p>{{ end }}
+<h2>Errors</h2>
+<ol>{{ range .Errors }}<li><code>{{ . }}</code></li>{{ end }}</ol>
+<h2>Request</h2>
+<table>
+<tr><th>Request ID</th><td>{{ .Problem.RequestID }}</td></tr>
+<tr><th>Method</th><td>{{ .Method }}</td></tr>
+<tr><th>URL</th><td>{{ .URL }}</td></tr>
+<tr><th>Route</th><td>{{ .Route }}</td></tr>
+{{ range $k, $v := .Args }}<tr><th>{{ $k }}</th><td>{{ $v }}</td></tr>{{ end }}
+</table>
+<h2>Headers</h2>
+<table>{{ range .Headers }}<tr><th>{{ .Name }}</th><td>{{ range .Values }}{{ . }}<br>{{ end }}</td></tr>{{ end }}</table>
+<h2>Stack</h2>
+<pre>{{ .Stack }}</pre>
+</body>
+</html>
+`))
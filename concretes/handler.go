@@ -0,0 +1,102 @@
+package concretes
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/go-mango/mango/contracts"
+)
+
+var (
+	contextType = reflect.TypeOf((*contracts.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// Adapt converts any supported handler form to contracts.Callable,
+// the signature is checked here so that requests pay no reflection
+// cost for it. It panics on unsupported handlers.
+func Adapt(h contracts.Handler) contracts.Callable {
+	switch fn := h.(type) {
+	case contracts.Callable:
+		return fn
+	case func(contracts.Context) (int, interface{}):
+		return fn
+	case func(contracts.Context) error:
+		return func(ctx contracts.Context) (int, interface{}) {
+			if err := fn(ctx); err != nil {
+				return 0, err
+			}
+
+			return 0, nil
+		}
+	case func(contracts.Context) (interface{}, error):
+		return func(ctx contracts.Context) (int, interface{}) {
+			v, err := fn(ctx)
+			if err != nil {
+				return 0, err
+			}
+
+			if v == nil {
+				return http.StatusNoContent, nil
+			}
+
+			return http.StatusOK, v
+		}
+	case func(http.ResponseWriter, *http.Request):
+		return adaptHTTP(http.HandlerFunc(fn))
+	case http.Handler:
+		return adaptHTTP(fn)
+	}
+
+	return adaptTyped(h)
+}
+
+func adaptHTTP(h http.Handler) contracts.Callable {
+	return func(ctx contracts.Context) (int, interface{}) {
+		r := ctx.Request().Parent().WithContext(ctx.Std())
+		h.ServeHTTP(NewResponseWriter(ctx.Response()), r)
+		return 0, nil
+	}
+}
+
+// adaptTyped adapts func(Context, *Input) (*Output, error).
+func adaptTyped(h contracts.Handler) contracts.Callable {
+	fn := reflect.ValueOf(h)
+	t := fn.Type()
+
+	if t.Kind() != reflect.Func ||
+		t.NumIn() != 2 || t.In(0) != contextType ||
+		t.In(1).Kind() != reflect.Ptr || t.In(1).Elem().Kind() != reflect.Struct ||
+		t.NumOut() != 2 || t.Out(1) != errorType {
+		panic(fmt.Sprintf("unsupported handler signature %T.", h))
+	}
+
+	input := t.In(1).Elem()
+	b := binderOf(input)
+
+	return func(ctx contracts.Context) (int, interface{}) {
+		in := reflect.New(input)
+
+		if err := b.bind(ctx, in.Elem()); err != nil {
+			return 0, err
+		}
+
+		if err := b.validate(in); err != nil {
+			return 0, err
+		}
+
+		out := fn.Call([]reflect.Value{reflect.ValueOf(ctx), in})
+
+		if err, _ := out[1].Interface().(error); err != nil {
+			return 0, err
+		}
+
+		v := out[0]
+		if !v.IsValid() || ((v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil()) {
+			return http.StatusNoContent, nil
+		}
+
+		return http.StatusOK, v.Interface()
+	}
+}
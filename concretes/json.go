@@ -0,0 +1,109 @@
+package concretes
+
+import (
+	"encoding/json"
+	"io"
+	"regexp"
+
+	"github.com/go-mango/mango/contracts"
+)
+
+// jsonpCallback restricts JSONP callbacks to dotted identifiers.
+var jsonpCallback = regexp.MustCompile(`^[A-Za-z_$][\w$]*(\.[A-Za-z_$][\w$]*)*$`)
+
+type stdJSON struct{}
+
+func (stdJSON) NewEncoder(w io.Writer) contracts.JSONEncoder {
+	return json.NewEncoder(w)
+}
+
+func (stdJSON) NewDecoder(r io.Reader) contracts.JSONDecoder {
+	return json.NewDecoder(r)
+}
+
+// jsonSettings is JSON configuration resolved for a single request.
+type jsonSettings struct {
+	opt      *contracts.JSONOption
+	pretty   bool
+	callback string
+}
+
+func (s *jsonSettings) engine() contracts.JSONEngine {
+	if s.opt == nil || s.opt.Engine == nil {
+		return stdJSON{}
+	}
+
+	return s.opt.Engine
+}
+
+func (s *jsonSettings) encoder(w io.Writer, pretty bool) contracts.JSONEncoder {
+	e := s.engine().NewEncoder(w)
+
+	if s.opt != nil && s.opt.DisableHTMLEscape {
+		e.SetEscapeHTML(false)
+	}
+
+	if pretty && s.pretty {
+		e.SetIndent("", "  ")
+	}
+
+	return e
+}
+
+func (s *jsonSettings) decoder(r io.Reader) contracts.JSONDecoder {
+	d := s.engine().NewDecoder(r)
+
+	if s.opt != nil && s.opt.DisallowUnknownFields {
+		d.DisallowUnknownFields()
+	}
+
+	if s.opt != nil && s.opt.UseNumber {
+		d.UseNumber()
+	}
+
+	return d
+}
+
+// bindJSON applies JSON option of application to request and response.
+func bindJSON(req contracts.Request, res contracts.Response, services *Services) {
+	opt := services.JSON
+	if opt == nil {
+		opt = &contracts.JSONOption{}
+	}
+
+	settings := &jsonSettings{opt: opt}
+	query := req.URL().Query()
+
+	param := opt.PrettyParam
+	if param == "" {
+		param = "pretty"
+	}
+
+	if _, ok := query[param]; ok && services.Mode == contracts.DevelopmentMode {
+		settings.pretty = true
+	}
+
+	if opt.JSONPParam != "" {
+		if cb := query.Get(opt.JSONPParam); jsonpCallback.MatchString(cb) {
+			settings.callback = cb
+		}
+	}
+
+	if r, ok := req.(*request); ok {
+		r.json = settings
+	}
+
+	if r, ok := res.(*response); ok {
+		r.json = settings
+	}
+}
+
+// jsonOf returns JSON settings of response, defaults are used for
+// foreign implementations.
+func jsonOf(res contracts.Response) *jsonSettings {
+	if r, ok := res.(*response); ok && r.json != nil {
+		return r.json
+	}
+
+	return &jsonSettings{}
+}
@@ -0,0 +1,164 @@
+package concretes
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/go-mango/mango/contracts"
+)
+
+// ErrInvalidSignature is returned when signed value is tampered
+// or signed by an unknown key.
+var ErrInvalidSignature = errors.New("mango: invalid signature")
+
+// ErrInvalidCiphertext is returned when encrypted value can not be
+// decrypted with any known key.
+var ErrInvalidCiphertext = errors.New("mango: invalid ciphertext")
+
+var encoding = base64.RawURLEncoding
+
+type keyringKey struct {
+	sign []byte
+	aead cipher.AEAD
+}
+
+type keyring struct {
+	keys  []*keyringKey
+	mutex sync.RWMutex
+}
+
+// NewKeyring creates keyring with given secrets, the first one is used
+// for signing and encryption, others are kept for verification only.
+// A random secret is generated when none is given, values protected by
+// it will not survive restarts.
+func NewKeyring(secrets ...[]byte) contracts.Keyring {
+	k := &keyring{}
+
+	if len(secrets) == 0 {
+		secret := make([]byte, 32)
+		if _, err := io.ReadFull(rand.Reader, secret); err != nil {
+			panic(err)
+		}
+
+		secrets = append(secrets, secret)
+	}
+
+	for i := len(secrets) - 1; i >= 0; i-- {
+		k.Rotate(secrets[i])
+	}
+
+	return k
+}
+
+// deriveKey derives purpose bound sub key from secret.
+func deriveKey(secret []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
+// Rotate makes given secret the primary key.
+func (k *keyring) Rotate(secret []byte) {
+	block, err := aes.NewCipher(deriveKey(secret, "mango.encrypt"))
+	if err != nil {
+		panic(err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		panic(err)
+	}
+
+	k.mutex.Lock()
+	k.keys = append([]*keyringKey{{deriveKey(secret, "mango.sign"), aead}}, k.keys...)
+	k.mutex.Unlock()
+}
+
+func (k *keyring) snapshot() []*keyringKey {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+	return k.keys
+}
+
+func mac(key []byte, name, value string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(name))
+	h.Write([]byte{0})
+	h.Write([]byte(value))
+	return h.Sum(nil)
+}
+
+// Sign signs value with primary key, name is bound to the signature
+// so that signed value can not be moved to another cookie.
+func (k *keyring) Sign(name, value string) string {
+	sum := mac(k.snapshot()[0].sign, name, value)
+	return encoding.EncodeToString([]byte(value)) + "." + encoding.EncodeToString(sum)
+}
+
+// Verify checks signed value against all known keys.
+func (k *keyring) Verify(name, signed string) (string, error) {
+	i := strings.LastIndexByte(signed, '.')
+	if i < 0 {
+		return "", ErrInvalidSignature
+	}
+
+	value, err := encoding.DecodeString(signed[:i])
+	if err != nil {
+		return "", ErrInvalidSignature
+	}
+
+	sum, err := encoding.DecodeString(signed[i+1:])
+	if err != nil {
+		return "", ErrInvalidSignature
+	}
+
+	for _, key := range k.snapshot() {
+		if hmac.Equal(sum, mac(key.sign, name, string(value))) {
+			return string(value), nil
+		}
+	}
+
+	return "", ErrInvalidSignature
+}
+
+// Encrypt seals value with primary key using AES-GCM.
+func (k *keyring) Encrypt(name, value string) (string, error) {
+	aead := k.snapshot()[0].aead
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	return encoding.EncodeToString(aead.Seal(nonce, nonce, []byte(value), []byte(name))), nil
+}
+
+// Decrypt opens sealed value with any known key.
+func (k *keyring) Decrypt(name, sealed string) (string, error) {
+	raw, err := encoding.DecodeString(sealed)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+
+	for _, key := range k.snapshot() {
+		n := key.aead.NonceSize()
+		if len(raw) < n {
+			return "", ErrInvalidCiphertext
+		}
+
+		plain, err := key.aead.Open(nil, raw[:n], raw[n:], []byte(name))
+		if err == nil {
+			return string(plain), nil
+		}
+	}
+
+	return "", ErrInvalidCiphertext
+}
@@ -0,0 +1,116 @@
+package concretes
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKeyringRoundTrip(t *testing.T) {
+	k := NewKeyring([]byte("secret"))
+
+	for _, value := range []string{"", "hello", "a.b.c", strings.Repeat("x", 4096)} {
+		v, err := k.Verify("name", k.Sign("name", value))
+		if err != nil || v != value {
+			t.Errorf("Verify(Sign(%.10q)) = %.10q, %v", value, v, err)
+		}
+
+		sealed, err := k.Encrypt("name", value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		v, err = k.Decrypt("name", sealed)
+		if err != nil || v != value {
+			t.Errorf("Decrypt(Encrypt(%.10q)) = %.10q, %v", value, v, err)
+		}
+	}
+}
+
+func TestKeyringRotation(t *testing.T) {
+	old := NewKeyring([]byte("old"))
+	signed := old.Sign("name", "value")
+	sealed, _ := old.Encrypt("name", "value")
+
+	rotated := NewKeyring([]byte("new"), []byte("old"))
+	if v, err := rotated.Verify("name", signed); err != nil || v != "value" {
+		t.Errorf("old signature rejected after rotation: %q, %v", v, err)
+	}
+
+	if v, err := rotated.Decrypt("name", sealed); err != nil || v != "value" {
+		t.Errorf("old ciphertext rejected after rotation: %q, %v", v, err)
+	}
+
+	if _, err := NewKeyring([]byte("old")).Verify("name", rotated.Sign("name", "value")); err != ErrInvalidSignature {
+		t.Errorf("value signed by new key verified by old keyring: %v", err)
+	}
+
+	old.Rotate([]byte("new"))
+	if v, err := rotated.Verify("name", old.Sign("name", "value")); err != nil || v != "value" {
+		t.Errorf("Rotate did not make secret primary: %q, %v", v, err)
+	}
+
+	dropped := NewKeyring([]byte("new"))
+	if _, err := dropped.Verify("name", signed); err != ErrInvalidSignature {
+		t.Errorf("signature of dropped key accepted: %v", err)
+	}
+
+	if _, err := dropped.Decrypt("name", sealed); err != ErrInvalidCiphertext {
+		t.Errorf("ciphertext of dropped key accepted: %v", err)
+	}
+}
+
+func TestKeyringTampered(t *testing.T) {
+	k := NewKeyring([]byte("secret"))
+	signed := k.Sign("name", "value")
+	sealed, _ := k.Encrypt("name", "value")
+
+	for _, tt := range []struct {
+		name, signed string
+	}{
+		{"name", ""},
+		{"name", "nodot"},
+		{"name", "!!!." + signed[strings.LastIndexByte(signed, '.')+1:]},
+		{"name", signed[:strings.LastIndexByte(signed, '.')+1] + "!!!"},
+		{"name", encoding.EncodeToString([]byte("other")) + signed[strings.LastIndexByte(signed, '.'):]},
+		{"name", signed + "x"},
+		{"name", flipMiddle(signed)},
+		{"other", signed},
+	} {
+		if _, err := k.Verify(tt.name, tt.signed); err != ErrInvalidSignature {
+			t.Errorf("Verify(%q, %q) = %v, want ErrInvalidSignature", tt.name, tt.signed, err)
+		}
+	}
+
+	for _, tt := range []struct {
+		name, sealed string
+	}{
+		{"name", ""},
+		{"name", "!!!"},
+		{"name", sealed[:8]},
+		{"name", flipMiddle(sealed)},
+		{"other", sealed},
+	} {
+		if _, err := k.Decrypt(tt.name, tt.sealed); err != ErrInvalidCiphertext {
+			t.Errorf("Decrypt(%q, %q) = %v, want ErrInvalidCiphertext", tt.name, tt.sealed, err)
+		}
+	}
+}
+
+func TestKeyringRandomSecret(t *testing.T) {
+	a, b := NewKeyring(), NewKeyring()
+
+	if _, err := b.Verify("name", a.Sign("name", "value")); err != ErrInvalidSignature {
+		t.Errorf("keyrings with random secrets share keys: %v", err)
+	}
+}
+
+// flipMiddle replaces middle character of s with a different one,
+// the last one may only carry padding bits.
+func flipMiddle(s string) string {
+	i := len(s) / 2
+	if s[i] == 'A' {
+		return s[:i] + "B" + s[i+1:]
+	}
+
+	return s[:i] + "A" + s[i+1:]
+}
@@ -0,0 +1,231 @@
+package concretes
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-mango/mango/contracts"
+)
+
+// PaginateOption configures how pagination parameters are read.
+type PaginateOption struct {
+	// Cursor switches to cursor mode, pages are addressed by opaque
+	// cursors instead of page numbers.
+	Cursor bool
+	// PerPage is page size used when client does not ask for one, defaults to 20.
+	PerPage int
+	// MaxPerPage caps page size asked by client, defaults to 100.
+	MaxPerPage int
+	// Names of query parameters, default to "page", "per_page" and "cursor".
+	PageParam    string
+	PerPageParam string
+	CursorParam  string
+}
+
+// Paginator carries pagination parameters of a list request and
+// renders the page it is filled with. Link headers, X-Total-Count
+// and an envelope of data, meta and links are written.
+//
+/*
+	p, err := concretes.Paginate(ctx, concretes.PaginateOption{})
+	if err != nil {
+		return 0, err
+	}
+
+	p.Items, p.Total = repo.List(p.Offset(), p.PerPage)
+
+	return 200, p
+*/
+type Paginator struct {
+	Page    int
+	PerPage int
+	Cursor  string
+
+	// Items of current page.
+	Items interface{}
+	// Total number of items, negative when unknown.
+	Total int
+	// NextCursor and PrevCursor address adjacent pages in cursor
+	// mode, empty cursors mean there are no such pages.
+	NextCursor string
+	PrevCursor string
+
+	opt PaginateOption
+	url url.URL
+}
+
+type pageMeta struct {
+	Page       int    `json:"page,omitempty" xml:"page,omitempty" yaml:"page,omitempty"`
+	PerPage    int    `json:"per_page" xml:"per_page" yaml:"per_page"`
+	Total      *int   `json:"total,omitempty" xml:"total,omitempty" yaml:"total,omitempty"`
+	TotalPages *int   `json:"total_pages,omitempty" xml:"total_pages,omitempty" yaml:"total_pages,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty" xml:"next_cursor,omitempty" yaml:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty" xml:"prev_cursor,omitempty" yaml:"prev_cursor,omitempty"`
+}
+
+type pageLinks struct {
+	First string `json:"first,omitempty" xml:"first,omitempty" yaml:"first,omitempty"`
+	Prev  string `json:"prev,omitempty" xml:"prev,omitempty" yaml:"prev,omitempty"`
+	Next  string `json:"next,omitempty" xml:"next,omitempty" yaml:"next,omitempty"`
+	Last  string `json:"last,omitempty" xml:"last,omitempty" yaml:"last,omitempty"`
+}
+
+const maxInt = int(^uint(0) >> 1)
+
+// Paginate reads pagination parameters of incoming request, malformed
+// parameters are reported as 400 errors.
+func Paginate(ctx contracts.Context, opt PaginateOption) (*Paginator, error) {
+	if opt.PerPage <= 0 {
+		opt.PerPage = 20
+	}
+
+	if opt.MaxPerPage <= 0 {
+		opt.MaxPerPage = 100
+	}
+
+	if opt.PageParam == "" {
+		opt.PageParam = "page"
+	}
+
+	if opt.PerPageParam == "" {
+		opt.PerPageParam = "per_page"
+	}
+
+	if opt.CursorParam == "" {
+		opt.CursorParam = "cursor"
+	}
+
+	u := ctx.Request().URL()
+	query := u.Query()
+
+	p := &Paginator{
+		Page:    1,
+		PerPage: opt.PerPage,
+		Cursor:  query.Get(opt.CursorParam),
+		Total:   -1,
+		opt:     opt,
+		url:     url.URL{Path: u.Path, RawQuery: u.RawQuery},
+	}
+
+	invalid := map[string]string{}
+
+	if s := query.Get(opt.PerPageParam); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			invalid[opt.PerPageParam] = "must be a positive integer"
+		} else if n > opt.MaxPerPage {
+			p.PerPage = opt.MaxPerPage
+		} else {
+			p.PerPage = n
+		}
+	}
+
+	if s := query.Get(opt.PageParam); s != "" && !opt.Cursor {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			invalid[opt.PageParam] = "must be a positive integer"
+		} else if n > maxInt/p.PerPage { //Offset() and Offset()+PerPage must not overflow.
+			invalid[opt.PageParam] = "is too large"
+		} else {
+			p.Page = n
+		}
+	}
+
+	if len(invalid) > 0 {
+		return nil, &contracts.HTTPError{Code: http.StatusBadRequest, Message: "invalid pagination parameters", Details: invalid}
+	}
+
+	return p, nil
+}
+
+// Offset returns number of items before current page.
+func (p *Paginator) Offset() int {
+	return (p.Page - 1) * p.PerPage
+}
+
+// Limit returns page size, it is an alias of PerPage.
+func (p *Paginator) Limit() int {
+	return p.PerPage
+}
+
+// Render writes page to response.
+func (p *Paginator) Render(ctx contracts.Context) error {
+	meta := pageMeta{PerPage: p.PerPage}
+	links := pageLinks{}
+
+	if p.opt.Cursor {
+		meta.NextCursor = p.NextCursor
+		meta.PrevCursor = p.PrevCursor
+
+		links.First = p.link(p.opt.CursorParam, "")
+		if p.PrevCursor != "" {
+			links.Prev = p.link(p.opt.CursorParam, p.PrevCursor)
+		}
+
+		if p.NextCursor != "" {
+			links.Next = p.link(p.opt.CursorParam, p.NextCursor)
+		}
+	} else {
+		meta.Page = p.Page
+
+		links.First = p.link(p.opt.PageParam, "1")
+		if p.Page > 1 {
+			links.Prev = p.link(p.opt.PageParam, strconv.Itoa(p.Page-1))
+		}
+
+		if p.Total >= 0 {
+			pages := (p.Total + p.PerPage - 1) / p.PerPage
+			if pages < 1 {
+				pages = 1
+			}
+
+			meta.TotalPages = &pages
+			links.Last = p.link(p.opt.PageParam, strconv.Itoa(pages))
+
+			if p.Page < pages {
+				links.Next = p.link(p.opt.PageParam, strconv.Itoa(p.Page+1))
+			}
+		}
+	}
+
+	if p.Total >= 0 {
+		total := p.Total
+		meta.Total = &total
+		ctx.Response().Header().Set("X-Total-Count", strconv.Itoa(p.Total))
+	}
+
+	header := []string{}
+	for _, l := range []struct{ rel, href string }{
+		{"first", links.First},
+		{"prev", links.Prev},
+		{"next", links.Next},
+		{"last", links.Last},
+	} {
+		if l.href != "" {
+			header = append(header, "<"+l.href+`>; rel="`+l.rel+`"`)
+		}
+	}
+
+	ctx.Response().Header().Set("Link", strings.Join(header, ", "))
+
+	return handleEncodable(ctx, &Envelope{Data: p.Items, Meta: meta, Links: links})
+}
+
+// link builds URL of current request with param replaced,
+// empty value removes param.
+func (p *Paginator) link(param, value string) string {
+	u := p.url
+	query := u.Query()
+
+	if value == "" {
+		query.Del(param)
+	} else {
+		query.Set(param, value)
+	}
+
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
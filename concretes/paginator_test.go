@@ -0,0 +1,49 @@
+package concretes
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/go-mango/mango/contracts"
+)
+
+func TestPaginate(t *testing.T) {
+	for _, tt := range []struct {
+		query   string
+		page    int
+		perPage int
+		invalid bool
+	}{
+		{"", 1, 20, false},
+		{"?page=3&per_page=10", 3, 10, false},
+		{"?per_page=1000", 1, 100, false},
+		{"?page=0", 0, 0, true},
+		{"?page=-1", 0, 0, true},
+		{"?page=x", 0, 0, true},
+		{"?per_page=0", 0, 0, true},
+		{"?page=9223372036854775807", 0, 0, true},
+		{"?page=99999999999999999999", 0, 0, true},
+		{"?page=" + strconv.Itoa(maxInt/20), maxInt / 20, 20, false},
+		{"?page=" + strconv.Itoa(maxInt/20+1), 0, 0, true},
+	} {
+		p, err := Paginate(newTestContext("GET", "/items"+tt.query, nil), PaginateOption{})
+
+		if tt.invalid {
+			if he, ok := err.(*contracts.HTTPError); !ok || he.Code != http.StatusBadRequest {
+				t.Errorf("Paginate(%s) = %v, want 400", tt.query, err)
+			}
+
+			continue
+		}
+
+		if err != nil || p.Page != tt.page || p.PerPage != tt.perPage {
+			t.Errorf("Paginate(%s) = %+v, %v", tt.query, p, err)
+			continue
+		}
+
+		if p.Offset() < 0 || p.Offset()+p.PerPage < p.Offset() {
+			t.Errorf("Paginate(%s): Offset() = %d overflows", tt.query, p.Offset())
+		}
+	}
+}
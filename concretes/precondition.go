@@ -0,0 +1,196 @@
+package concretes
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-mango/mango/contracts"
+)
+
+// ErrNotModified is returned by CheckPreconditions once 304 Not Modified
+// has been written, handlers return it and nothing else is rendered.
+var ErrNotModified = errors.New("mango: not modified")
+
+// parseETags splits list of entity tags of If-Match or If-None-Match.
+func parseETags(header string) []string {
+	tags := []string{}
+
+	for header = strings.TrimSpace(header); header != ""; header = strings.TrimSpace(header) {
+		if header[0] == ',' {
+			header = header[1:]
+			continue
+		}
+
+		if header[0] == '*' {
+			tags = append(tags, "*")
+			header = header[1:]
+			continue
+		}
+
+		start := 0
+		if strings.HasPrefix(header, "W/") {
+			start = 2
+		}
+
+		if len(header) <= start || header[start] != '"' {
+			break
+		}
+
+		end := strings.IndexByte(header[start+1:], '"')
+		if end < 0 {
+			break
+		}
+
+		end += start + 2
+		tags = append(tags, header[:end])
+		header = header[end:]
+	}
+
+	return tags
+}
+
+// weakMatch compares entity tags ignoring weakness.
+func weakMatch(a, b string) bool {
+	return strings.TrimPrefix(a, "W/") == strings.TrimPrefix(b, "W/")
+}
+
+// strongMatch compares entity tags that both must be strong.
+func strongMatch(a, b string) bool {
+	return !strings.HasPrefix(a, "W/") && !strings.HasPrefix(b, "W/") && a == b
+}
+
+// FormatETag quotes raw version as an entity tag, quoted and weak
+// tags are returned as is.
+func FormatETag(tag string) string {
+	if tag == "" || strings.HasPrefix(tag, "\"") || strings.HasPrefix(tag, "W/\"") {
+		return tag
+	}
+
+	return "\"" + tag + "\""
+}
+
+// matchAny reports whether tag matches any of tags in header.
+func matchAny(header, tag string, match func(a, b string) bool) bool {
+	for _, t := range parseETags(header) {
+		if t == "*" {
+			return tag != ""
+		}
+
+		if tag != "" && match(t, tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NotModified evaluates If-None-Match and If-Modified-Since of GET and
+// HEAD requests against ETag and Last-Modified set on response.
+func NotModified(ctx contracts.Context) bool {
+	r := ctx.Request()
+	if r.Method() != http.MethodGet && r.Method() != http.MethodHead {
+		return false
+	}
+
+	h := ctx.Response().Header()
+
+	if inm := r.Header().Get("If-None-Match"); inm != "" {
+		return matchAny(inm, h.Get("ETag"), weakMatch)
+	}
+
+	ims, err := http.ParseTime(r.Header().Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	modified, err := http.ParseTime(h.Get("Last-Modified"))
+	if err != nil {
+		return false
+	}
+
+	return !modified.Truncate(time.Second).After(ims)
+}
+
+// WriteNotModified turns response into 304 Not Modified.
+func WriteNotModified(ctx contracts.Context) {
+	ctx.Response().Clear()
+	ctx.Response().SetStatus(http.StatusNotModified)
+
+	h := ctx.Response().Header()
+	h.Del("Content-Type")
+	h.Del("Content-Length")
+	h.Del("Content-Encoding")
+}
+
+// CheckPreconditions evaluates If-Match, If-Unmodified-Since and
+// If-None-Match against current version of the resource. It returns
+// *contracts.HTTPError with 412 when a precondition fails or 428 when
+// required is set and the request is unconditional. Safe methods that
+// match If-None-Match get 304 written with validators and ErrNotModified
+// returned, validators are also set when they pass. Writes leave them to
+// the handler as the version changes, see SetVersion.
+func CheckPreconditions(ctx contracts.Context, current contracts.Version, required bool) error {
+	r := ctx.Request()
+	h := r.Header()
+	tag := FormatETag(current.ETag)
+	safe := r.Method() == http.MethodGet || r.Method() == http.MethodHead
+
+	if required && !safe && h.Get("If-Match") == "" && h.Get("If-Unmodified-Since") == "" && h.Get("If-None-Match") == "" {
+		return &contracts.HTTPError{
+			Code:    http.StatusPreconditionRequired,
+			Message: "request must be conditional, send If-Match, If-None-Match or If-Unmodified-Since",
+		}
+	}
+
+	failed := &contracts.HTTPError{Code: http.StatusPreconditionFailed, Message: "resource has been modified"}
+
+	if im := h.Get("If-Match"); im != "" {
+		if !current.Exists() || !matchAny(im, tag, strongMatch) {
+			return failed
+		}
+	} else if ius, err := http.ParseTime(h.Get("If-Unmodified-Since")); err == nil {
+		if !current.Exists() || current.Modified.Truncate(time.Second).After(ius) {
+			return failed
+		}
+	}
+
+	if inm := h.Get("If-None-Match"); inm != "" {
+		matched := false
+		for _, t := range parseETags(inm) {
+			if (t == "*" && current.Exists()) || (tag != "" && weakMatch(t, tag)) {
+				matched = true
+				break
+			}
+		}
+
+		if matched {
+			if safe {
+				WriteNotModified(ctx)
+				SetVersion(ctx, current)
+				return ErrNotModified
+			}
+
+			return &contracts.HTTPError{Code: http.StatusPreconditionFailed, Message: "resource already exists"}
+		}
+	}
+
+	if safe {
+		SetVersion(ctx, current)
+	}
+
+	return nil
+}
+
+// SetVersion sets ETag and Last-Modified of response, handlers of writes
+// call it with the version they produced once preconditions passed.
+func SetVersion(ctx contracts.Context, v contracts.Version) {
+	if v.ETag != "" {
+		ctx.Response().Header().Set("ETag", FormatETag(v.ETag))
+	}
+
+	if !v.Modified.IsZero() {
+		ctx.Response().Header().Set("Last-Modified", v.Modified.UTC().Format(http.TimeFormat))
+	}
+}
@@ -0,0 +1,133 @@
+package concretes
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-mango/mango/contracts"
+)
+
+func TestParseETags(t *testing.T) {
+	for _, tt := range []struct {
+		header string
+		want   []string
+	}{
+		{"", []string{}},
+		{"*", []string{"*"}},
+		{`"a"`, []string{`"a"`}},
+		{`W/"a"`, []string{`W/"a"`}},
+		{`"a", W/"b" ,"c,d"`, []string{`"a"`, `W/"b"`, `"c,d"`}},
+		{`"a", bogus, "b"`, []string{`"a"`}},
+		{`"unterminated`, []string{}},
+		{`W/`, []string{}},
+	} {
+		if got := parseETags(tt.header); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseETags(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestCheckPreconditions(t *testing.T) {
+	modified := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	current := contracts.Version{ETag: "v1", Modified: modified}
+	before := modified.Add(-time.Hour).Format(http.TimeFormat)
+	after := modified.Add(time.Hour).Format(http.TimeFormat)
+
+	for _, tt := range []struct {
+		method   string
+		header   map[string]string
+		current  contracts.Version
+		required bool
+		code     int
+	}{
+		{"GET", nil, current, false, 0},
+		{"GET", nil, current, true, 0},
+		{"PUT", nil, current, false, 0},
+		{"PUT", nil, current, true, http.StatusPreconditionRequired},
+
+		{"PUT", map[string]string{"If-Match": `"v1"`}, current, true, 0},
+		{"PUT", map[string]string{"If-Match": `"v0", "v1"`}, current, true, 0},
+		{"PUT", map[string]string{"If-Match": `*`}, current, true, 0},
+		{"PUT", map[string]string{"If-Match": `"v0"`}, current, true, http.StatusPreconditionFailed},
+		{"PUT", map[string]string{"If-Match": `W/"v1"`}, current, true, http.StatusPreconditionFailed},
+		{"PUT", map[string]string{"If-Match": `"v1"`}, contracts.Version{ETag: "W/\"v1\""}, true, http.StatusPreconditionFailed},
+		{"PUT", map[string]string{"If-Match": `*`}, contracts.Version{}, true, http.StatusPreconditionFailed},
+		{"PUT", map[string]string{"If-Match": `"v0"`, "If-Unmodified-Since": after}, current, true, http.StatusPreconditionFailed},
+
+		{"PUT", map[string]string{"If-Unmodified-Since": after}, current, true, 0},
+		{"PUT", map[string]string{"If-Unmodified-Since": before}, current, true, http.StatusPreconditionFailed},
+		{"PUT", map[string]string{"If-Unmodified-Since": "garbage"}, current, false, 0},
+
+		{"GET", map[string]string{"If-None-Match": `"v1"`}, current, false, http.StatusNotModified},
+		{"HEAD", map[string]string{"If-None-Match": `W/"v1"`}, current, false, http.StatusNotModified},
+		{"GET", map[string]string{"If-None-Match": `"v0", "v1"`}, current, false, http.StatusNotModified},
+		{"GET", map[string]string{"If-None-Match": `*`}, current, false, http.StatusNotModified},
+		{"GET", map[string]string{"If-None-Match": `*`}, contracts.Version{}, false, 0},
+		{"GET", map[string]string{"If-None-Match": `"v0"`}, current, false, 0},
+		{"PUT", map[string]string{"If-None-Match": `*`}, current, true, http.StatusPreconditionFailed},
+		{"PUT", map[string]string{"If-None-Match": `*`}, contracts.Version{}, true, 0},
+		{"PUT", map[string]string{"If-None-Match": `"v1"`}, current, true, http.StatusPreconditionFailed},
+		{"PUT", map[string]string{"If-Match": `"v1"`, "If-None-Match": `"v1"`}, current, true, http.StatusPreconditionFailed},
+	} {
+		ctx := newTestContext(tt.method, "/doc", tt.header)
+		err := CheckPreconditions(ctx, tt.current, tt.required)
+
+		code := 0
+		switch e := err.(type) {
+		case nil:
+		case *contracts.HTTPError:
+			code = e.Code
+		default:
+			if err == ErrNotModified {
+				code = ctx.Response().Status()
+			}
+		}
+
+		if code != tt.code {
+			t.Errorf("%s %v against %+v = %v, want %d", tt.method, tt.header, tt.current, err, tt.code)
+			continue
+		}
+
+		want := ""
+		if tt.method == "GET" || tt.method == "HEAD" {
+			want = FormatETag(tt.current.ETag)
+		}
+
+		if code != 0 && code != http.StatusNotModified {
+			want = ""
+		}
+
+		if tag := ctx.Response().Header().Get("ETag"); tag != want {
+			t.Errorf("%s %v: ETag = %q, want %q", tt.method, tt.header, tag, want)
+		}
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	modified := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	for _, tt := range []struct {
+		method string
+		header map[string]string
+		want   bool
+	}{
+		{"GET", nil, false},
+		{"GET", map[string]string{"If-None-Match": `"v1"`}, true},
+		{"GET", map[string]string{"If-None-Match": `W/"v1"`}, true},
+		{"GET", map[string]string{"If-None-Match": `"v2"`}, false},
+		{"GET", map[string]string{"If-None-Match": `"v2"`, "If-Modified-Since": modified.Format(http.TimeFormat)}, false},
+		{"GET", map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)}, true},
+		{"GET", map[string]string{"If-Modified-Since": modified.Add(-time.Second).Format(http.TimeFormat)}, false},
+		{"HEAD", map[string]string{"If-None-Match": `*`}, true},
+		{"POST", map[string]string{"If-None-Match": `"v1"`}, false},
+	} {
+		ctx := newTestContext(tt.method, "/doc", tt.header)
+		SetVersion(ctx, contracts.Version{ETag: "v1", Modified: modified.Add(500 * time.Millisecond)})
+
+		if got := NotModified(ctx); got != tt.want {
+			t.Errorf("NotModified(%s %v) = %v, want %v", tt.method, tt.header, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package concretes
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/go-mango/mango/contracts"
+)
+
+type rendererEntry struct {
+	t  reflect.Type
+	fn contracts.RenderFunc
+}
+
+var renderers = struct {
+	entries []rendererEntry
+	mutex   sync.RWMutex
+}{}
+
+// RegisterRenderer registers fn to render return values of type t,
+// it allows third party types to control their own encoding. When t is
+// an interface type it applies to all types implementing it.
+//
+/*
+	concretes.RegisterRenderer(reflect.TypeOf(Page{}), renderPage)
+	concretes.RegisterRenderer(reflect.TypeOf((*proto.Message)(nil)).Elem(), renderProto)
+*/
+func RegisterRenderer(t reflect.Type, fn contracts.RenderFunc) {
+	renderers.mutex.Lock()
+	defer renderers.mutex.Unlock()
+
+	for i, e := range renderers.entries {
+		if e.t == t {
+			renderers.entries[i].fn = fn
+			return
+		}
+	}
+
+	renderers.entries = append(renderers.entries, rendererEntry{t, fn})
+}
+
+// rendererFor finds renderer of t, exact types win over interfaces.
+func rendererFor(t reflect.Type) (contracts.RenderFunc, bool) {
+	renderers.mutex.RLock()
+	defer renderers.mutex.RUnlock()
+
+	for _, e := range renderers.entries {
+		if e.t == t {
+			return e.fn, true
+		}
+	}
+
+	for _, e := range renderers.entries {
+		if e.t.Kind() == reflect.Interface && t.Implements(e.t) {
+			return e.fn, true
+		}
+	}
+
+	return nil, false
+}
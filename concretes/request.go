@@ -6,7 +6,8 @@ import (
 	"net/url"
 	"strings"
 
-	"io/ioutil"
+	"errors"
+	"io"
 
 	"github.com/go-mango/mango/contracts"
 
@@ -16,6 +17,8 @@ import (
 type request struct {
 	parent *http.Request
 	args   map[string]string
+	id     string
+	json   *jsonSettings
 }
 
 // NewRequest create new request instance.
@@ -23,6 +26,8 @@ func NewRequest(parent *http.Request) contracts.Request {
 	return &request{
 		parent,
 		map[string]string{},
+		"",
+		nil,
 	}
 }
 
@@ -36,6 +41,16 @@ func (request *request) Args() map[string]string {
 	return request.args
 }
 
+// ID returns correlation ID of incoming requests.
+func (request *request) ID() string {
+	return request.id
+}
+
+// SetID set correlation ID for incoming requests.
+func (request *request) SetID(id string) {
+	request.id = id
+}
+
 // Parent returns original http.Request
 func (request *request) Parent() *http.Request {
 	return request.parent
@@ -80,6 +95,16 @@ func (request *request) Query(k string) string {
 	return request.parent.URL.Query().Get(k)
 }
 
+// Cookie retrieves raw value of named cookie.
+func (request *request) Cookie(name string) string {
+	c, err := request.parent.Cookie(name)
+	if err != nil {
+		return ""
+	}
+
+	return c.Value
+}
+
 // Param retrieves value from PATH params.
 func (request *request) Arg(k string) string {
 	if v, ok := request.args[k]; ok {
@@ -98,14 +123,25 @@ func (request *request) Input(k string) string {
 	return request.Query(k)
 }
 
-// JSON parse request body as JSON.
+// JSON parse request body as JSON with settings of application.
 func (request *request) JSON(v interface{}) error {
-	data, err := ioutil.ReadAll(request.parent.Body)
-	if err != nil {
+	settings := request.json
+	if settings == nil {
+		settings = &jsonSettings{}
+	}
+
+	d := settings.decoder(request.parent.Body)
+	if err := d.Decode(v); err != nil {
 		return err
 	}
 
-	return json.Unmarshal(data, v)
+	//trailing data is as malformed as with json.Unmarshal.
+	var extra json.RawMessage
+	if err := d.Decode(&extra); err != io.EOF {
+		return errors.New("json: unexpected data after top-level value")
+	}
+
+	return nil
 }
 
 // IsTLS detects the request is over HTTPS or not.
@@ -0,0 +1,95 @@
+package concretes
+
+import (
+	"net"
+
+	"github.com/go-mango/mango/contracts"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// RequestIDHeader is the header carries correlation ID of requests.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength limits the size of accepted incoming request ID.
+const maxRequestIDLength = 128
+
+// ResolveRequestID returns request ID sent by a trusted source,
+// or generates a new one when it is absent or untrusted.
+func ResolveRequestID(r contracts.Request, trusted []*net.IPNet) string {
+	if id := r.Header().Get(RequestIDHeader); id != "" && validRequestID(id) {
+		if isTrusted(r.Parent().RemoteAddr, trusted) {
+			return id
+		}
+	}
+
+	return uuid.NewV4().String()
+}
+
+// ParseTrusted parses trusted sources from CIDR notations or plain IPs.
+func ParseTrusted(sources ...string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(sources))
+
+	for _, s := range sources {
+		if ip := net.ParseIP(s); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * net.IPv4len
+			}
+
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, err
+		}
+
+		nets = append(nets, n)
+	}
+
+	return nets, nil
+}
+
+// IsTrusted reports whether request comes directly from a trusted source.
+func IsTrusted(r contracts.Request, trusted []*net.IPNet) bool {
+	return isTrusted(r.Parent().RemoteAddr, trusted)
+}
+
+func isTrusted(addr string, trusted []*net.IPNet) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, n := range trusted {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validRequestID rejects oversized IDs and IDs with characters
+// that may break log lines or headers.
+func validRequestID(id string) bool {
+	if len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
@@ -1,18 +1,27 @@
 package concretes
 
 import (
+	"bufio"
 	"bytes"
-	"encoding/json"
+	"errors"
 	"io"
+	"net"
 	"net/http"
+	"time"
 
 	"github.com/go-mango/mango/contracts"
 )
 
 type response struct {
-	parent http.ResponseWriter
-	io     *bytes.Buffer
-	status int
+	parent    http.ResponseWriter
+	io        *bytes.Buffer
+	status    int
+	streaming bool
+	committed bool
+	buffering bool
+	written   int
+	json      *jsonSettings
+	before    []func(contracts.Response)
 }
 
 // NewResponse create new response instance.
@@ -21,6 +30,12 @@ func NewResponse(parent http.ResponseWriter) contracts.Response {
 		parent,
 		&bytes.Buffer{},
 		http.StatusOK,
+		false,
+		false,
+		false,
+		0,
+		nil,
+		nil,
 	}
 }
 
@@ -29,8 +44,15 @@ func (r *response) Parent() http.ResponseWriter {
 	return r.parent
 }
 
-//Write response data to buffer.
+//Write response data to buffer, or to client directly in streaming mode.
 func (r *response) Write(b []byte) (int, error) {
+	if r.streaming {
+		r.commit()
+		n, err := r.parent.Write(b)
+		r.written += n
+		return n, err
+	}
+
 	return r.io.Write(b)
 }
 
@@ -39,8 +61,28 @@ func (r *response) WriteString(s string) (int, error) {
 	return r.Write([]byte(s))
 }
 
+//WriteJSON encodes v as JSON with settings of application, it is
+//wrapped in JSONP callback when client asked for one.
 func (r *response) WriteJSON(v interface{}) error {
-	return json.NewEncoder(r).Encode(v)
+	settings := jsonOf(r)
+
+	if settings.callback == "" {
+		return settings.encoder(r, true).Encode(v)
+	}
+
+	r.Header().Set("Content-Type", "application/javascript; charset=utf-8")
+	r.Header().Set("X-Content-Type-Options", "nosniff")
+
+	if _, err := r.WriteString("/**/" + settings.callback + "("); err != nil {
+		return err
+	}
+
+	if err := settings.encoder(r, true).Encode(v); err != nil {
+		return err
+	}
+
+	_, err := r.WriteString(");")
+	return err
 }
 
 //Header returns http.Header.
@@ -55,6 +97,10 @@ func (r *response) Clear() {
 
 //Size returns total size of response body.
 func (r *response) Size() int {
+	if r.streaming {
+		return r.written
+	}
+
 	return r.io.Len()
 }
 
@@ -70,7 +116,12 @@ func (r *response) SetStatus(c int) {
 
 // Send sends all buffered data to client.
 func (r *response) Send() error {
-	r.parent.WriteHeader(r.status)
+	if r.streaming {
+		r.commit()
+		return nil
+	}
+
+	r.commit()
 	_, e := io.Copy(r.parent, r.io)
 
 	if e != nil {
@@ -82,6 +133,115 @@ func (r *response) Send() error {
 	return nil
 }
 
+// commit writes status and headers to client once.
+func (r *response) commit() {
+	if r.committed {
+		return
+	}
+
+	r.committed = true
+
+	for _, fn := range r.before {
+		fn(r)
+	}
+
+	r.parent.WriteHeader(r.status)
+}
+
+// OnBeforeSend registers fn called right before status and headers are
+// sent, it is the last chance to change them. Hooks run in order of
+// registration and are skipped for hijacked connections.
+func (r *response) OnBeforeSend(fn func(contracts.Response)) {
+	r.before = append(r.before, fn)
+}
+
+// Hijack takes over the underlying connection, status and headers are
+// not sent by mango afterwards so the caller owns the whole connection.
+func (r *response) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if r.committed {
+		return nil, nil, errors.New("mango: response already committed")
+	}
+
+	h, ok := r.parent.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("mango: connection does not support hijacking")
+	}
+
+	conn, rw, err := h.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.committed = true
+	r.streaming = true
+
+	return conn, rw, nil
+}
+
+// Committed reports whether status and headers were sent to client.
+func (r *response) Committed() bool {
+	return r.committed
+}
+
+// Stream switches response to streaming mode and calls fn with a writer
+// connected to client directly, writes are flushed as they happen.
+// fn writes to buffer instead when buffering is required by middlewares.
+// Data buffered before is sent first.
+func (r *response) Stream(fn func(io.Writer) error) error {
+	if r.buffering {
+		return fn(r)
+	}
+
+	r.streaming = true
+
+	if r.io.Len() > 0 { //sends what was written before streaming started.
+		pending := r.io.Bytes()
+		r.io = &bytes.Buffer{}
+
+		if _, err := r.Write(pending); err != nil {
+			return err
+		}
+	}
+
+	err := fn(&flushWriter{r})
+	r.Flush()
+
+	return err
+}
+
+// IsStreaming reports whether response is in streaming mode.
+func (r *response) IsStreaming() bool {
+	return r.streaming
+}
+
+// RequireBuffering keeps response buffered, middlewares that
+// post-process the whole body call it before ctx.Next().
+func (r *response) RequireBuffering() {
+	r.buffering = true
+}
+
+// Flush sends written data to client in streaming mode.
+func (r *response) Flush() {
+	if !r.streaming {
+		return
+	}
+
+	r.commit()
+	if f, ok := r.parent.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+type flushWriter struct {
+	r *response
+}
+
+func (w *flushWriter) Write(b []byte) (int, error) {
+	n, err := w.r.Write(b)
+	w.r.Flush()
+	return n, err
+}
+
 // SetCookie add a cookie to response header.
 func (r *response) SetCookie(c *http.Cookie) {
 	http.SetCookie(r.parent, c)
@@ -89,10 +249,22 @@ func (r *response) SetCookie(c *http.Cookie) {
 
 // DelCookie delete specified cookie.
 func (r *response) DelCookie(name string) {
+	r.ExpireCookie(&http.Cookie{Name: name})
+}
+
+// ExpireCookie deletes cookie set with a path or domain, they must
+// match the stored cookie for browsers to remove it.
+func (r *response) ExpireCookie(c *http.Cookie) {
 	http.SetCookie(r.parent, &http.Cookie{
-		Name:   name,
-		Value:  "",
-		MaxAge: -1,
+		Name:     c.Name,
+		Value:    "",
+		Path:     c.Path,
+		Domain:   c.Domain,
+		Expires:  time.Unix(0, 0),
+		MaxAge:   -1,
+		Secure:   c.Secure,
+		HttpOnly: c.HttpOnly,
+		SameSite: c.SameSite,
 	})
 }
 
@@ -107,3 +279,33 @@ func (r *response) Redirect(status int, to string) (int, interface{}) {
 func (r *response) Buffered() []byte {
 	return r.io.Bytes()
 }
+
+// snapshotWriter stands for client of a sent response, writes are discarded.
+type snapshotWriter struct {
+	header http.Header
+}
+
+func (w *snapshotWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *snapshotWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (w *snapshotWriter) WriteHeader(int) {}
+
+// snapshotResponse copies status, size and headers of a sent response.
+func snapshotResponse(res contracts.Response) contracts.Response {
+	return &response{
+		&snapshotWriter{res.Header().Clone()},
+		&bytes.Buffer{},
+		res.Status(),
+		true,
+		true,
+		false,
+		res.Size(),
+		nil,
+		nil,
+	}
+}
@@ -0,0 +1,32 @@
+package concretes
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResponseStreamSendsBufferedData(t *testing.T) {
+	for _, buffering := range []bool{false, true} {
+		w := httptest.NewRecorder()
+		res := NewResponse(w)
+		if buffering {
+			res.RequireBuffering()
+		}
+
+		res.WriteString("prefix-")
+
+		err := res.Stream(func(w io.Writer) error {
+			_, err := io.WriteString(w, "body")
+			return err
+		})
+
+		if err == nil {
+			err = res.Send()
+		}
+
+		if err != nil || w.Body.String() != "prefix-body" {
+			t.Errorf("buffering=%v: body = %q, %v, want %q", buffering, w.Body.String(), err, "prefix-body")
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package concretes
+
+import (
+	"net/http"
+
+	"github.com/go-mango/mango/contracts"
+)
+
+type responseWriter struct {
+	contracts.Response
+}
+
+// NewResponseWriter adapts contracts.Response to http.ResponseWriter.
+func NewResponseWriter(r contracts.Response) http.ResponseWriter {
+	return &responseWriter{r}
+}
+
+// WriteHeader sets response status.
+func (w *responseWriter) WriteHeader(code int) {
+	w.SetStatus(code)
+}
+
+// Flush sends written data to client in streaming mode.
+func (w *responseWriter) Flush() {
+	w.Response.Flush()
+}
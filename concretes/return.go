@@ -1,89 +1,210 @@
 package concretes
 
 import (
-	"encoding/json"
+	"bufio"
+	"bytes"
+	"errors"
 	"io"
 	"net/http"
 	"os"
 	"reflect"
 
-	"github.com/go-mango/logy"
 	"github.com/go-mango/mango/contracts"
 )
 
 func handleResponse(fn contracts.Callable) contracts.ThenableFunc {
 	return func(ctx contracts.ThenableContext) {
 		code, value := fn(ctx)
+		render(ctx, code, value)
+	}
+}
 
-		if code == 0 {
-			return
+// render writes value returned by handlers to response.
+func render(ctx contracts.Context, code int, value interface{}) {
+	if err, ok := value.(error); ok {
+		if err != ErrNotModified {
+			ctx.Error(ToHTTPError(code, err))
 		}
 
-		ctx.Response().SetStatus(code)
+		return
+	}
 
-		t := reflect.ValueOf(value)
+	if code == 0 {
+		return
+	}
 
-		if !t.IsValid() {
-			return
-		}
+	ctx.Response().SetStatus(code)
+
+	t := reflect.ValueOf(value)
 
-		if code == http.StatusPermanentRedirect || code == http.StatusTemporaryRedirect {
-			if target, ok := value.(string); ok {
-				if target != "" {
-					ctx.Response().Redirect(code, target)
-				}
-			} else {
-				panic("trying redirect to an invalid URL.")
+	if !t.IsValid() {
+		return
+	}
+
+	if code == http.StatusPermanentRedirect || code == http.StatusTemporaryRedirect {
+		if target, ok := value.(string); ok {
+			if target != "" {
+				ctx.Response().Redirect(code, target)
 			}
+		} else {
+			panic("trying redirect to an invalid URL.")
+		}
+
+		return
+	}
+
+	if r, ok := value.(contracts.Renderable); ok {
+		if err := r.Render(ctx); err != nil {
+			handleError(ctx, err)
+		}
+
+		return
+	}
 
+	if fn, ok := rendererFor(t.Type()); ok {
+		if err := fn(ctx, value); err != nil {
+			handleError(ctx, err)
+		}
+
+		return
+	}
+
+	if seq, ok := sequenceOf(value); ok {
+		if err := handleSequence(ctx, seq); err != nil {
+			handleError(ctx, err)
+		}
+
+		return
+	}
+
+	switch value.(type) {
+	case []byte:
+		_, err := ctx.Response().Write(value.([]byte))
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+	case string:
+		_, err := ctx.Response().WriteString(value.(string))
+		if err != nil {
+			handleError(ctx, err)
 			return
 		}
+	case *os.File:
+		file := value.(*os.File)
 
-		switch value.(type) {
-		case []byte:
-			_, err := ctx.Response().Write(value.([]byte))
-			if err != nil {
-				handleError(ctx, err)
-			}
-		case string:
-			_, err := ctx.Response().WriteString(value.(string))
-			if err != nil {
-				handleError(ctx, err)
-			}
-		case *os.File:
-			file := value.(*os.File)
+		defer file.Close()
 
-			defer file.Close()
+		ctx.Response().Header().Set("Content-Disposition", attachment(file.Name()))
 
-			_, err := io.Copy(ctx.Response(), file)
-			if err != nil {
+		if stat, err := file.Stat(); err == nil && stat.Mode().IsRegular() {
+			if err := ServeContent(ctx, stat.Name(), stat.ModTime(), file); err != nil {
 				handleError(ctx, err)
 			}
 
-			ctx.Response().Header().Set("Content-Disposition", "attachment; filename=\""+file.Name()+"\"")
-		case io.Reader:
-			_, err := io.Copy(ctx.Response(), value.(io.Reader))
-			if err != nil {
-				handleError(ctx, err)
-			}
-		default:
-			handleJsonable(ctx, value)
+			return
 		}
 
+		if err := handleReader(ctx, file); err != nil {
+			handleError(ctx, err)
+		}
+
+		return
+	case io.Reader:
+		if err := handleReader(ctx, value.(io.Reader)); err != nil {
+			handleError(ctx, err)
+		}
+
+		return
+	default:
+		if err := handleEncodable(ctx, value); err != nil {
+			handleError(ctx, err)
+		}
+
+		return
+	}
+
+	if ctx.Response().Header().Get("Content-Type") == "" {
 		ctx.Response().Header().Set("Content-Type", http.DetectContentType(ctx.Response().Buffered()))
 	}
 }
 
+// handleReader streams content of reader to client, Content-Type is
+// sniffed from the first bytes when handler did not set it.
+func handleReader(ctx contracts.Context, reader io.Reader) error {
+	br := bufio.NewReaderSize(reader, 512)
+
+	if ctx.Response().Header().Get("Content-Type") == "" {
+		head, _ := br.Peek(512)
+		ctx.Response().Header().Set("Content-Type", http.DetectContentType(head))
+	}
+
+	return ctx.Response().Stream(func(w io.Writer) error {
+		_, err := io.Copy(w, br)
+		return err
+	})
+}
+
 func handleError(ctx contracts.Context, err error) {
-	ctx.Response().SetStatus(http.StatusInternalServerError)
-	ctx.Response().Clear()
-	logy.Std().Warn("RETURN:", err.Error())
+	if err == ErrNotModified {
+		return
+	}
+
+	ctx.Error(ToHTTPError(http.StatusInternalServerError, err))
 }
 
-func handleJsonable(ctx contracts.Context, v interface{}) {
-	e := json.NewEncoder(ctx.Response())
-	err := e.Encode(v)
+// handleEncodable encodes v with codec of Content-Type set by handler,
+// or with the one negotiated from Accept header. Negotiated codecs that
+// can not represent v give way to the next acceptable one.
+func handleEncodable(ctx contracts.Context, v interface{}) error {
+	if codec, ok := CodecFor(ctx.Response().Header().Get("Content-Type")); ok {
+		return encode(ctx, codec, v, false)
+	}
+
+	candidates, err := negotiateCodecs(ctx.Request().Header().Get("Accept"))
 	if err != nil {
-		handleError(ctx, err)
+		return err
 	}
+
+	ctx.Response().Header().Add("Vary", "Accept")
+
+	for i, codec := range candidates {
+		ctx.Response().Header().Set("Content-Type", codec.ContentType())
+
+		err = encode(ctx, codec, v, i < len(candidates)-1)
+		if !isUnencodable(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+// encode transforms and encodes v, with probe set it is encoded into
+// a buffer first so that nothing is written when codec fails.
+func encode(ctx contracts.Context, codec contracts.Codec, v interface{}, probe bool) error {
+	code, v, err := transform(ctx, ctx.Response().Status(), v)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := codec.(jsonCodec); !ok && probe {
+		buf := &bytes.Buffer{}
+		if err := codec.Encode(buf, v); err != nil {
+			return err
+		}
+
+		ctx.Response().SetStatus(code)
+		_, err := ctx.Response().Write(buf.Bytes())
+		return err
+	}
+
+	ctx.Response().SetStatus(code)
+
+	return codec.Encode(ctx.Response(), v)
+}
+
+func isUnencodable(err error) bool {
+	var he *contracts.HTTPError
+	return errors.As(err, &he) && he.Code == http.StatusNotAcceptable
 }
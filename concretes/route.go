@@ -1,6 +1,7 @@
 package concretes
 
 import (
+	"net/url"
 	"regexp"
 
 	"github.com/go-mango/mango/contracts"
@@ -15,6 +16,7 @@ type route struct {
 	callable  contracts.Callable
 	thenStack []contracts.ThenableFunc
 	isStatic  bool
+	name      string
 }
 
 // NewRoute returns route instance.
@@ -28,6 +30,7 @@ func NewRoute(method string, path string, callable contracts.Callable, stack ...
 		callable,
 		stack,
 		isStatic,
+		"",
 	}
 }
 
@@ -86,3 +89,20 @@ func (route *route) ThenStack() []contracts.ThenableFunc {
 func (route *route) IsStatic() bool {
 	return route.isStatic
 }
+
+func (route *route) Name() string {
+	return route.name
+}
+
+// SetName names the route so that URLs of it can be generated.
+func (route *route) SetName(name string) contracts.Route {
+	route.name = name
+	return route
+}
+
+// URL generates URL path of the route with given path variables.
+func (route *route) URL(params map[string]string) string {
+	return re.ReplaceAllStringFunc(route.path, func(v string) string {
+		return url.PathEscape(params[v[1:len(v)-1]])
+	})
+}
@@ -29,8 +29,23 @@ func NewRouter() contracts.Router {
 	}
 }
 
-func (router *router) SetDefaultRoute(callable contracts.Callable) {
-	router.defaultRoute = NewRoute("*", "/", callable)
+func (router *router) SetDefaultRoute(handler contracts.Handler) {
+	router.defaultRoute = NewRoute("*", "/", Adapt(handler))
+}
+
+// Named finds route by name, nil is returned if it does not exist.
+func (router *router) Named(name string) contracts.Route {
+	for _, pool := range []map[string][]contracts.Route{router.staticPool, router.pool} {
+		for _, batch := range pool {
+			for _, route := range batch {
+				if route.Name() == name {
+					return route
+				}
+			}
+		}
+	}
+
+	return nil
 }
 
 func (router *router) push(route contracts.Route) {
@@ -112,7 +127,7 @@ func (router *router) SetThenableStack(stack ...contracts.ThenableFunc) {
 func (router *router) Group(prefix string, entry func(contracts.Router), stack ...contracts.ThenableFunc) {
 	router.pushScope(prefix)
 	savedStack := router.ThenableStack()
-	router.Use(router.stack...)
+	router.Use(stack...)
 
 	entry(router)
 
@@ -132,40 +147,53 @@ func (router *router) popScope() {
 func (router *router) newScopedRoute(
 	method string,
 	path string,
-	resolver contracts.Callable,
+	resolver contracts.Handler,
 	stack ...contracts.ThenableFunc,
-) {
+) contracts.Route {
 	router.pushScope(path)
 	path = strings.Join(router.prefixes, "/")
-	stack = append(router.stack, stack...)
-	router.push(NewRoute(method, path, resolver, stack...))
+	stack = append(append([]contracts.ThenableFunc{}, router.stack...), stack...) //routes must not share backing arrays.
+	route := NewRoute(method, path, Adapt(resolver), stack...)
+	router.push(route)
 	router.popScope()
+
+	return route
 }
 
 // Any register resolver function for route prefixed with "prefix".
-func (router *router) Any(path string, resolver contracts.Callable, stack ...contracts.ThenableFunc) {
-	router.Get(path, resolver, stack...)
+// The GET route is returned.
+func (router *router) Any(path string, resolver contracts.Handler, stack ...contracts.ThenableFunc) contracts.Route {
+	resolver = Adapt(resolver)
+	route := router.Get(path, resolver, stack...)
 	router.Post(path, resolver, stack...)
 	router.Put(path, resolver, stack...)
 	router.Delete(path, resolver, stack...)
+
+	return route
 }
 
 // Get register resolver function called by GET requests.
-func (router *router) Get(path string, resolver contracts.Callable, stack ...contracts.ThenableFunc) {
-	router.newScopedRoute("GET", path, resolver, stack...)
+func (router *router) Get(path string, resolver contracts.Handler, stack ...contracts.ThenableFunc) contracts.Route {
+	return router.newScopedRoute("GET", path, resolver, stack...)
 }
 
 // Post register resolver function called by POST requests.
-func (router *router) Post(path string, resolver contracts.Callable, stack ...contracts.ThenableFunc) {
-	router.newScopedRoute("POST", path, resolver, stack...)
+func (router *router) Post(path string, resolver contracts.Handler, stack ...contracts.ThenableFunc) contracts.Route {
+	return router.newScopedRoute("POST", path, resolver, stack...)
 }
 
 // Put register resolver function called by PUT requests.
-func (router *router) Put(path string, resolver contracts.Callable, stack ...contracts.ThenableFunc) {
-	router.newScopedRoute("PUT", path, resolver, stack...)
+func (router *router) Put(path string, resolver contracts.Handler, stack ...contracts.ThenableFunc) contracts.Route {
+	return router.newScopedRoute("PUT", path, resolver, stack...)
 }
 
 // Delete register resolver function called by DELETE requests.
-func (router *router) Delete(path string, resolver contracts.Callable, stack ...contracts.ThenableFunc) {
-	router.newScopedRoute("DELETE", path, resolver, stack...)
+func (router *router) Delete(path string, resolver contracts.Handler, stack ...contracts.ThenableFunc) contracts.Route {
+	return router.newScopedRoute("DELETE", path, resolver, stack...)
+}
+
+// WebSocket register handler of WebSocket connections upgraded from GET requests,
+// only same origin browsers are allowed, use WebSocketHandler for other options.
+func (router *router) WebSocket(path string, handler contracts.WebSocketHandler, stack ...contracts.ThenableFunc) contracts.Route {
+	return router.Get(path, WebSocketHandler(handler, WebSocketOption{}), stack...)
 }
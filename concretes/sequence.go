@@ -0,0 +1,206 @@
+package concretes
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/go-mango/mango/contracts"
+)
+
+// SequenceFlushInterval is how often streamed sequences are flushed
+// to client, values are buffered in between.
+var SequenceFlushInterval = time.Second
+
+// sequenceFormats are media types sequences are streamed as, in order
+// of preference. JSON is streamed as an array, others one value per line.
+var sequenceFormats = []string{
+	"application/json",
+	"application/x-ndjson",
+	"application/jsonl",
+	"application/x-jsonlines",
+}
+
+// sequence reads values from a receive channel or contracts.Iterator.
+type sequence interface {
+	next(stop <-chan struct{}, tick <-chan time.Time) (v interface{}, ok bool, ticked bool)
+	err() error
+	close()
+}
+
+// sequenceOf reports whether v is streamed value by value.
+func sequenceOf(v interface{}) (sequence, bool) {
+	if it, ok := v.(contracts.Iterator); ok {
+		return &iteratorSequence{it}, true
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Chan && rv.Type().ChanDir()&reflect.RecvDir != 0 {
+		return &chanSequence{ch: rv}, true
+	}
+
+	return nil, false
+}
+
+type chanSequence struct {
+	ch reflect.Value
+}
+
+func (s *chanSequence) next(stop <-chan struct{}, tick <-chan time.Time) (interface{}, bool, bool) {
+	chosen, v, ok := reflect.Select([]reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: s.ch},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(stop)},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(tick)},
+	})
+
+	switch chosen {
+	case 0:
+		if !ok {
+			return nil, false, false
+		}
+
+		return v.Interface(), true, false
+	case 2:
+		return nil, true, true
+	}
+
+	return nil, false, false
+}
+
+func (s *chanSequence) err() error {
+	return nil
+}
+
+func (s *chanSequence) close() {}
+
+type iteratorSequence struct {
+	it contracts.Iterator
+}
+
+func (s *iteratorSequence) next(stop <-chan struct{}, tick <-chan time.Time) (interface{}, bool, bool) {
+	select {
+	case <-stop:
+		return nil, false, false
+	case <-tick:
+		return nil, true, true
+	default:
+	}
+
+	if !s.it.Next() {
+		return nil, false, false
+	}
+
+	return s.it.Value(), true, false
+}
+
+func (s *iteratorSequence) err() error {
+	return s.it.Err()
+}
+
+func (s *iteratorSequence) close() {
+	if c, ok := s.it.(io.Closer); ok {
+		c.Close()
+	}
+}
+
+// negotiateSequence picks streaming format by Content-Type set by
+// handler or by Accept header.
+func negotiateSequence(ctx contracts.Context) (string, error) {
+	if mediaType, _, err := mime.ParseMediaType(ctx.Response().Header().Get("Content-Type")); err == nil {
+		for _, f := range sequenceFormats {
+			if f == mediaType {
+				return f, nil
+			}
+		}
+	}
+
+	accept := ctx.Request().Header().Get("Accept")
+	if accept == "" {
+		accept = "application/json"
+	}
+
+	for _, r := range parseAccept(accept) {
+		for _, f := range sequenceFormats {
+			if matchMediaRange(r.mediaType, f) {
+				return f, nil
+			}
+		}
+	}
+
+	return "", &contracts.HTTPError{
+		Code:    http.StatusNotAcceptable,
+		Message: "none of acceptable media types is supported",
+		Details: sequenceFormats,
+	}
+}
+
+// handleSequence streams values as they are produced, it stops when
+// client disconnects or the sequence ends.
+func handleSequence(ctx contracts.Context, seq sequence) error {
+	defer seq.close()
+
+	format, err := negotiateSequence(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx.Response().Header().Set("Content-Type", format)
+	ctx.Response().Header().Add("Vary", "Accept")
+	ctx.Response().Header().Set("X-Accel-Buffering", "no")
+
+	array := format == "application/json"
+
+	return ctx.Response().Stream(func(w io.Writer) error {
+		bw := bufio.NewWriter(w)
+		encoder := jsonOf(ctx.Response()).encoder(bw, false)
+
+		ticker := time.NewTicker(SequenceFlushInterval)
+		defer ticker.Stop()
+
+		if array {
+			bw.WriteByte('[')
+		}
+
+		for i := 0; ; {
+			v, ok, ticked := seq.next(ctx.Std().Done(), ticker.C)
+			if !ok {
+				break
+			}
+
+			if ticked {
+				if err := bw.Flush(); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			if array && i > 0 {
+				bw.WriteByte(',')
+			}
+
+			if err := encoder.Encode(v); err != nil { //one value per line.
+				return err
+			}
+
+			i++
+		}
+
+		if ctx.Std().Err() != nil {
+			return nil //nobody is listening.
+		}
+
+		if err := seq.err(); err != nil {
+			return err
+		}
+
+		if array {
+			bw.WriteByte(']')
+		}
+
+		return bw.Flush()
+	})
+}
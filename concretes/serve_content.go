@@ -0,0 +1,32 @@
+package concretes
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/go-mango/mango/contracts"
+)
+
+// ServeContent streams content honouring Range, If-Range, If-Match,
+// If-None-Match, If-Modified-Since and If-Unmodified-Since, it replies
+// with 206 (including multipart byteranges), 304, 412 or 416 as needed.
+// Content-Type is derived from name when it is not set.
+func ServeContent(ctx contracts.Context, name string, modtime time.Time, content io.ReadSeeker) error {
+	r := ctx.Request().Parent()
+
+	return ctx.Response().Stream(func(w io.Writer) error {
+		http.ServeContent(NewResponseWriter(ctx.Response()), r, name, modtime, content)
+		return nil
+	})
+}
+
+// attachment returns Content-Disposition of a download named after
+// the base name of path, the directory is never disclosed.
+func attachment(path string) string {
+	return mime.FormatMediaType("attachment", map[string]string{
+		"filename": filepath.Base(path),
+	})
+}
@@ -0,0 +1,20 @@
+package concretes
+
+import (
+	"github.com/go-mango/mango/contracts"
+)
+
+// Services holds application wide providers shared by all contexts.
+type Services struct {
+	Cache   contracts.Cachable
+	Keyring contracts.Keyring
+	OnError contracts.ErrorHandler
+	Mode    string
+	Router  contracts.Router
+	Views   contracts.ViewEngine
+	JSON    *contracts.JSONOption
+	Workers *WorkerPool
+	// ErrorRenderers render error pages by status code, 0 is used
+	// for codes without their own renderer.
+	ErrorRenderers map[int]contracts.ErrorRenderer
+}
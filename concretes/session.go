@@ -1,21 +1,229 @@
 package concretes
 
 import (
+	"bytes"
+	"crypto/rand"
+	"encoding/gob"
+	"io"
+	"sync"
+	"time"
+
 	"github.com/go-mango/mango/contracts"
 )
 
-type session map[string]interface{}
+// sessionRecord is the persisted form of session,
+// custom value types must be registered by gob.Register.
+type sessionRecord struct {
+	Values   map[string]interface{}
+	Flashes  map[string]interface{}
+	Created  time.Time
+	Accessed time.Time
+}
+
+type session struct {
+	id       string
+	previous string
+	values   map[string]interface{}
+	flashes  map[string]interface{}
+	flashed  map[string]interface{}
+	created  time.Time
+	loaded   bool
+	dirty    bool
+	mutex    sync.RWMutex
+}
+
+// newSessionID generates random session ID.
+func newSessionID() string {
+	b := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		panic(err)
+	}
+
+	return encoding.EncodeToString(b)
+}
 
-// NewSession create session storage instance.
+// NewSession creates an empty session with a fresh ID.
 func NewSession() contracts.Session {
-	return session{}
+	return &session{
+		newSessionID(),
+		"",
+		map[string]interface{}{},
+		map[string]interface{}{},
+		map[string]interface{}{},
+		time.Now(),
+		false,
+		false,
+		sync.RWMutex{},
+	}
 }
 
-func (s session) Set(key string, value interface{}) {
-	s[key] = value
+// ID returns session ID.
+func (s *session) ID() string {
+	return s.id
 }
 
-func (s session) Get(key string) (value interface{}, ok bool) {
-	value, ok = s[key]
+// Get retrieves stored value.
+func (s *session) Get(key string) (value interface{}, ok bool) {
+	s.mutex.RLock()
+	value, ok = s.values[key]
+	s.mutex.RUnlock()
 	return
 }
+
+// Set stores value into session.
+func (s *session) Set(key string, value interface{}) {
+	s.mutex.Lock()
+	s.values[key] = value
+	s.dirty = true
+	s.mutex.Unlock()
+}
+
+// Delete removes stored value.
+func (s *session) Delete(key string) {
+	s.mutex.Lock()
+	delete(s.values, key)
+	s.dirty = true
+	s.mutex.Unlock()
+}
+
+// Flush removes all stored values and flash messages.
+func (s *session) Flush() {
+	s.mutex.Lock()
+	s.values = map[string]interface{}{}
+	s.flashes = map[string]interface{}{}
+	s.flashed = map[string]interface{}{}
+	s.dirty = true
+	s.mutex.Unlock()
+}
+
+// Regenerate issues a new session ID while keeping stored values,
+// it should be called on login to prevent session fixation.
+func (s *session) Regenerate() {
+	s.mutex.Lock()
+	if s.previous == "" {
+		s.previous = s.id
+	}
+	s.id = newSessionID()
+	s.dirty = true
+	s.mutex.Unlock()
+}
+
+// Flash stores value that is readable by the next request only.
+func (s *session) Flash(key string, value interface{}) {
+	s.mutex.Lock()
+	s.flashes[key] = value
+	s.dirty = true
+	s.mutex.Unlock()
+}
+
+// Flashed retrieves value flashed by previous request.
+func (s *session) Flashed(key string) (value interface{}, ok bool) {
+	s.mutex.RLock()
+	value, ok = s.flashed[key]
+	s.mutex.RUnlock()
+	return
+}
+
+// SessionManager loads and saves sessions through a store.
+type SessionManager struct {
+	Store       contracts.SessionStore
+	IdleTimeout time.Duration
+	Lifetime    time.Duration
+}
+
+// Load restores session by ID, a new session is returned when
+// it does not exist, can not be decoded or has been timed out.
+func (m *SessionManager) Load(ctx contracts.Context, id string) contracts.Session {
+	if id == "" {
+		return NewSession()
+	}
+
+	data, err := m.Store.Load(ctx, id)
+	if err != nil || data == nil {
+		return NewSession()
+	}
+
+	record := sessionRecord{}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&record); err != nil {
+		return NewSession()
+	}
+
+	now := time.Now()
+	if now.Sub(record.Accessed) > m.IdleTimeout || now.Sub(record.Created) > m.Lifetime {
+		m.Store.Destroy(ctx, id)
+		return NewSession()
+	}
+
+	if record.Values == nil {
+		record.Values = map[string]interface{}{}
+	}
+
+	if record.Flashes == nil {
+		record.Flashes = map[string]interface{}{}
+	}
+
+	return &session{
+		id,
+		"",
+		record.Values,
+		map[string]interface{}{},
+		record.Flashes,
+		record.Created,
+		true,
+		false,
+		sync.RWMutex{},
+	}
+}
+
+// Changed reports whether session was restored from store or modified
+// since it was last saved, untouched new sessions need not be saved.
+func (m *SessionManager) Changed(s contracts.Session) bool {
+	sess := s.(*session)
+
+	sess.mutex.RLock()
+	defer sess.mutex.RUnlock()
+
+	return sess.loaded || sess.dirty
+}
+
+// Save persists session and removes the one replaced by Regenerate.
+func (m *SessionManager) Save(ctx contracts.Context, s contracts.Session) error {
+	sess := s.(*session)
+
+	sess.mutex.Lock()
+	record := sessionRecord{sess.values, sess.flashes, sess.created, time.Now()}
+	previous := sess.previous
+	sess.previous = ""
+	sess.loaded = false
+	sess.dirty = false
+	sess.mutex.Unlock()
+
+	if previous != "" {
+		if err := m.Store.Destroy(ctx, previous); err != nil {
+			return err
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := gob.NewEncoder(buf).Encode(record); err != nil {
+		return err
+	}
+
+	return m.Store.Save(ctx, sess.ID(), buf.Bytes(), m.TTL(s))
+}
+
+// Expires returns the time session reaches its absolute lifetime.
+func (m *SessionManager) Expires(s contracts.Session) time.Time {
+	sess := s.(*session)
+	return sess.created.Add(m.Lifetime)
+}
+
+// TTL returns how long a saved session stays valid without access.
+func (m *SessionManager) TTL(s contracts.Session) time.Duration {
+	ttl := time.Until(m.Expires(s))
+	if ttl > m.IdleTimeout {
+		ttl = m.IdleTimeout
+	}
+
+	return ttl
+}
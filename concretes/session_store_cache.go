@@ -0,0 +1,39 @@
+package concretes
+
+import (
+	"time"
+
+	"github.com/go-mango/mango/contracts"
+)
+
+type cacheSessionStore struct {
+	cache  contracts.Cachable
+	prefix string
+}
+
+// NewCacheSessionStore creates session store on top of any Cachable,
+// data is stored as string so that remote caches like Redis work as well.
+func NewCacheSessionStore(cache contracts.Cachable, prefix string) contracts.SessionStore {
+	return &cacheSessionStore{cache, prefix}
+}
+
+func (s *cacheSessionStore) Load(ctx contracts.Context, id string) ([]byte, error) {
+	switch v := s.cache.Get(s.prefix + id).(type) {
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return v, nil
+	}
+
+	return nil, nil
+}
+
+func (s *cacheSessionStore) Save(ctx contracts.Context, id string, data []byte, ttl time.Duration) error {
+	s.cache.Set(s.prefix+id, string(data), ttl)
+	return nil
+}
+
+func (s *cacheSessionStore) Destroy(ctx contracts.Context, id string) error {
+	s.cache.Del(s.prefix + id)
+	return nil
+}
@@ -0,0 +1,64 @@
+package concretes
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-mango/mango/contracts"
+)
+
+// ErrCookieTooLarge is returned when encrypted session data does not
+// fit into a single cookie.
+var ErrCookieTooLarge = errors.New("mango: session data exceeds cookie size limit")
+
+// maxCookieSize is the size most browsers accept for a single cookie.
+const maxCookieSize = 4096
+
+type cookieSessionStore struct {
+	cookie http.Cookie
+}
+
+// NewCookieSessionStore creates session store that keeps data in an
+// encrypted client side cookie, attributes are copied from given cookie.
+func NewCookieSessionStore(cookie http.Cookie) contracts.SessionStore {
+	return &cookieSessionStore{cookie}
+}
+
+func (s *cookieSessionStore) Load(ctx contracts.Context, id string) ([]byte, error) {
+	value, err := ctx.EncryptedCookie(s.cookie.Name)
+	if err != nil {
+		return nil, nil
+	}
+
+	//data is bound to session ID to avoid mixing cookies of different sessions.
+	if !strings.HasPrefix(value, id+"|") {
+		return nil, nil
+	}
+
+	return []byte(value[len(id)+1:]), nil
+}
+
+func (s *cookieSessionStore) Save(ctx contracts.Context, id string, data []byte, ttl time.Duration) error {
+	sealed, err := ctx.Keyring().Encrypt(s.cookie.Name, id+"|"+string(data))
+	if err != nil {
+		return err
+	}
+
+	if len(sealed) > maxCookieSize {
+		return ErrCookieTooLarge
+	}
+
+	cookie := s.cookie
+	cookie.Value = sealed
+	cookie.Expires = time.Now().Add(ttl)
+	ctx.Response().SetCookie(&cookie)
+
+	return nil
+}
+
+func (s *cookieSessionStore) Destroy(ctx contracts.Context, id string) error {
+	ctx.Response().ExpireCookie(&s.cookie)
+	return nil
+}
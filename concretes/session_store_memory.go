@@ -0,0 +1,64 @@
+package concretes
+
+import (
+	"sync"
+	"time"
+
+	"github.com/go-mango/mango/contracts"
+)
+
+// memorySweepInterval is how often expired sessions are removed.
+const memorySweepInterval = time.Minute
+
+type memorySessionStore struct {
+	items map[string]*memoryItem
+	swept time.Time
+	mutex sync.Mutex
+}
+
+// NewMemorySessionStore creates session store that keeps data in process memory.
+func NewMemorySessionStore() contracts.SessionStore {
+	return &memorySessionStore{
+		map[string]*memoryItem{},
+		time.Now(),
+		sync.Mutex{},
+	}
+}
+
+func (s *memorySessionStore) Load(ctx contracts.Context, id string) ([]byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if v, ok := s.items[id]; ok && !v.Expired() {
+		return v.value.([]byte), nil
+	}
+
+	return nil, nil
+}
+
+func (s *memorySessionStore) Save(ctx contracts.Context, id string, data []byte, ttl time.Duration) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if time.Since(s.swept) > memorySweepInterval {
+		for i, v := range s.items {
+			if v.Expired() {
+				delete(s.items, i)
+			}
+		}
+
+		s.swept = time.Now()
+	}
+
+	s.items[id] = &memoryItem{data, time.Now().Add(ttl)}
+
+	return nil
+}
+
+func (s *memorySessionStore) Destroy(ctx contracts.Context, id string) error {
+	s.mutex.Lock()
+	delete(s.items, id)
+	s.mutex.Unlock()
+
+	return nil
+}
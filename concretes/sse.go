@@ -0,0 +1,158 @@
+package concretes
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-mango/mango/contracts"
+)
+
+// SSEHeartbeat is interval of comments sent to keep idle event streams open.
+var SSEHeartbeat = 15 * time.Second
+
+type eventStream struct {
+	ctx   contracts.Context
+	w     io.Writer
+	mutex sync.Mutex
+}
+
+// serveEvents holds the connection open as an event stream until fn returns,
+// it fails when middlewares require buffering as events would never be sent.
+func serveEvents(ctx contracts.Context, fn func(contracts.EventStream) error) (int, interface{}) {
+	if r, ok := ctx.Response().(*response); ok && r.buffering {
+		return 0, &contracts.HTTPError{
+			Code:    http.StatusInternalServerError,
+			Message: "event streams can not be served through buffering middlewares",
+		}
+	}
+
+	h := ctx.Response().Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	h.Set("X-Accel-Buffering", "no")
+
+	err := ctx.Response().Stream(func(w io.Writer) error {
+		stream := &eventStream{ctx: ctx, w: w}
+
+		stop := make(chan struct{})
+		done := make(chan struct{})
+		defer func() {
+			close(stop)
+			<-done //heartbeat must not write after fn returns.
+		}()
+
+		go func() {
+			defer close(done)
+			stream.heartbeat(stop)
+		}()
+
+		if _, err := stream.write([]byte(":ok\n\n")); err != nil {
+			return err
+		}
+
+		return fn(stream)
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return 0, nil
+}
+
+func (s *eventStream) heartbeat(stop <-chan struct{}) {
+	ticker := time.NewTicker(SSEHeartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := s.write([]byte(":ping\n\n")); err != nil {
+				return
+			}
+		case <-stop:
+			return
+		case <-s.Done():
+			return
+		}
+	}
+}
+
+func (s *eventStream) write(b []byte) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.w.Write(b)
+}
+
+// Send writes event to client and flushes it.
+func (s *eventStream) Send(e contracts.Event) error {
+	select {
+	case <-s.Done():
+		return s.ctx.Std().Err()
+	default:
+	}
+
+	buf := &bytes.Buffer{}
+
+	if e.ID != "" {
+		buf.WriteString("id: " + oneLine(e.ID) + "\n")
+	}
+
+	if e.Event != "" {
+		buf.WriteString("event: " + oneLine(e.Event) + "\n")
+	}
+
+	if e.Retry > 0 {
+		buf.WriteString("retry: " + strconv.FormatInt(int64(e.Retry/time.Millisecond), 10) + "\n")
+	}
+
+	var data string
+	switch v := e.Data.(type) {
+	case nil:
+	case string:
+		data = v
+	case []byte:
+		data = string(v)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+
+		data = string(b)
+	}
+
+	for _, line := range strings.Split(strings.Replace(data, "\r\n", "\n", -1), "\n") {
+		buf.WriteString("data: " + line + "\n")
+	}
+
+	buf.WriteString("\n")
+
+	_, err := s.write(buf.Bytes())
+	return err
+}
+
+// LastEventID returns ID of the last event received by a reconnecting client.
+func (s *eventStream) LastEventID() string {
+	if id := s.ctx.Request().Header().Get("Last-Event-ID"); id != "" {
+		return id
+	}
+
+	return s.ctx.Request().Query("lastEventId")
+}
+
+// Done is closed when client goes away or server shuts down.
+func (s *eventStream) Done() <-chan struct{} {
+	return s.ctx.Std().Done()
+}
+
+func oneLine(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
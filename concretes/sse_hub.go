@@ -0,0 +1,105 @@
+package concretes
+
+import (
+	"sync"
+
+	"github.com/go-mango/mango/contracts"
+)
+
+// hubBacklog is the number of events queued for a slow subscriber
+// before newer events are dropped for it.
+const hubBacklog = 32
+
+type subscriber struct {
+	stream contracts.EventStream
+	events chan contracts.Event
+	stop   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+}
+
+func (s *subscriber) close() {
+	s.once.Do(func() {
+		close(s.stop)
+	})
+}
+
+type eventHub struct {
+	topics map[string]map[*subscriber]struct{}
+	mutex  sync.RWMutex
+}
+
+// NewEventHub creates in-process hub broadcasting events to subscribers.
+func NewEventHub() contracts.EventHub {
+	return &eventHub{
+		map[string]map[*subscriber]struct{}{},
+		sync.RWMutex{},
+	}
+}
+
+// Subscribe delivers events of topic to stream until the returned
+// function is called or client of the stream goes away, no event is
+// sent to stream once the returned function returns.
+func (h *eventHub) Subscribe(topic string, stream contracts.EventStream) func() {
+	sub := &subscriber{
+		stream: stream,
+		events: make(chan contracts.Event, hubBacklog),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	h.mutex.Lock()
+	if _, ok := h.topics[topic]; !ok {
+		h.topics[topic] = map[*subscriber]struct{}{}
+	}
+	h.topics[topic][sub] = struct{}{}
+	h.mutex.Unlock()
+
+	go func() {
+		defer close(sub.done)
+		defer h.unsubscribe(topic, sub)
+
+		for {
+			select {
+			case e := <-sub.events:
+				if err := stream.Send(e); err != nil {
+					return
+				}
+			case <-stream.Done():
+				return
+			case <-sub.stop:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		sub.close()
+		<-sub.done
+	}
+}
+
+func (h *eventHub) unsubscribe(topic string, sub *subscriber) {
+	sub.close()
+
+	h.mutex.Lock()
+	delete(h.topics[topic], sub)
+	if len(h.topics[topic]) == 0 {
+		delete(h.topics, topic)
+	}
+	h.mutex.Unlock()
+}
+
+// Publish broadcasts event to subscribers of topic without blocking,
+// events are dropped for subscribers that fall too far behind.
+func (h *eventHub) Publish(topic string, event contracts.Event) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	for sub := range h.topics[topic] {
+		select {
+		case sub.events <- event:
+		default:
+		}
+	}
+}
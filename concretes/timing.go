@@ -0,0 +1,99 @@
+package concretes
+
+import (
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-mango/mango/contracts"
+)
+
+type timing struct {
+	name string
+	desc string
+	dur  time.Duration
+}
+
+// EnableTiming times the remaining middlewares and handler of ctx and
+// sends the results along with ctx.Timing metrics in Server-Timing header.
+func EnableTiming(ctx contracts.Context) {
+	c, ok := ctx.(*context)
+	if !ok || c.timed {
+		return
+	}
+
+	c.timed = true
+
+	ctx.Response().OnBeforeSend(func(res contracts.Response) {
+		c.mutex.RLock()
+		defer c.mutex.RUnlock()
+
+		metrics := make([]string, 0, len(c.timings))
+		for _, t := range c.timings {
+			m := t.name + ";dur=" + strconv.FormatFloat(float64(t.dur)/float64(time.Millisecond), 'f', 3, 64)
+			if t.desc != "" {
+				m += `;desc="` + t.desc + `"`
+			}
+
+			metrics = append(metrics, m)
+		}
+
+		if len(metrics) > 0 {
+			res.Header().Set("Server-Timing", strings.Join(metrics, ", "))
+		}
+	})
+}
+
+// timeStage runs stage of middleware chain, time spent in stages
+// it calls through Next is not counted as its own.
+func (c *context) timeStage(stage contracts.ThenableFunc, last bool) {
+	index := c.stage
+	c.stage++
+	c.nested = 0
+
+	start := time.Now()
+	stage(c)
+	total := time.Since(start)
+
+	self := total - c.nested
+	c.nested = total
+
+	if last {
+		c.record(timing{"handler", "", self})
+		return
+	}
+
+	c.record(timing{"mw" + strconv.Itoa(index), stageName(stage), self})
+}
+
+func (c *context) record(t timing) {
+	c.mutex.Lock()
+	c.timings = append(c.timings, t)
+	c.mutex.Unlock()
+}
+
+// stageName returns short name of middleware, e.g. "middlewares.Record".
+func stageName(fn contracts.ThenableFunc) string {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
+		return ""
+	}
+
+	name := f.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	for {
+		i := strings.LastIndex(name, ".func")
+		if i < 0 {
+			break
+		}
+
+		name = name[:i]
+	}
+
+	return strings.Replace(name, `"`, "", -1)
+}
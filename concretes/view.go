@@ -0,0 +1,220 @@
+package concretes
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/go-mango/mango/contracts"
+)
+
+// CSRFTokenKey is context key of CSRF token set by CSRF middleware,
+// it is also name of the form field rendered by csrf_field helper.
+const CSRFTokenKey = "csrf_token"
+
+// ErrNoViewEngine is returned when rendering views without an engine.
+var ErrNoViewEngine = errors.New("view engine is not set")
+
+// View is a return value rendered by application view engine.
+//
+/*
+	return 200, ctx.View("users/show", user)
+	return 200, &concretes.View{Name: "users/show", Data: user, Layout: "layouts/admin"}
+*/
+type View struct {
+	Name   string
+	Data   interface{}
+	Layout string
+}
+
+// Render renders view into response as HTML.
+func (v *View) Render(ctx contracts.Context) error {
+	engine := servicesOf(ctx).Views
+	if engine == nil {
+		return ErrNoViewEngine
+	}
+
+	buf := &bytes.Buffer{}
+	if err := engine.Render(ctx, buf, v.Name, v.Layout, v.Data); err != nil {
+		return err
+	}
+
+	if ctx.Response().Header().Get("Content-Type") == "" {
+		ctx.Response().Header().Set("Content-Type", "text/html; charset=utf-8")
+	}
+
+	_, err := ctx.Response().Write(buf.Bytes())
+	return err
+}
+
+// ViewOption configures view engine.
+type ViewOption struct {
+	// Dir is root directory of templates, template names are paths
+	// relative to it without extension, e.g. "users/show".
+	Dir string
+	// Ext is extension of template files, defaults to ".html".
+	Ext string
+	// Layout is default layout, it includes page with {{ yield }}.
+	Layout string
+	// Funcs are helpers shared by all templates.
+	Funcs template.FuncMap
+}
+
+type viewEngine struct {
+	opt   ViewOption
+	set   *template.Template
+	mutex sync.RWMutex
+}
+
+// NewViewEngine creates view engine on top of html/template, all
+// templates are parsed into one set so pages, layouts and partials can
+// refer to each other by name. Templates are compiled once here and
+// reloaded on every render in development mode.
+//
+// Built-in helpers:
+//
+/*
+	{{ yield }}                        renders page inside layout
+	{{ url "users.show" "id" .ID }}    generates URL of named route
+	{{ csrf_token }}                   CSRF token of current request
+	{{ csrf_field }}                   hidden form field holding the token
+*/
+func NewViewEngine(opt ViewOption) (contracts.ViewEngine, error) {
+	if opt.Ext == "" {
+		opt.Ext = ".html"
+	}
+
+	engine := &viewEngine{opt: opt}
+
+	set, err := engine.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	engine.set = set
+
+	return engine, nil
+}
+
+// parse compiles all templates under Dir, helpers are placeholders
+// here and bound to the request on render.
+func (e *viewEngine) parse() (*template.Template, error) {
+	set := template.New("").Funcs(e.helpers(nil, nil, "", nil))
+
+	err := filepath.Walk(e.opt.Dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || filepath.Ext(path) != e.opt.Ext {
+			return nil
+		}
+
+		rel, err := filepath.Rel(e.opt.Dir, path)
+		if err != nil {
+			return err
+		}
+
+		src, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		name := strings.TrimSuffix(filepath.ToSlash(rel), e.opt.Ext)
+		_, err = set.New(name).Parse(string(src))
+		return err
+	})
+
+	return set, err
+}
+
+// Render executes template name, wrapped in layout if there is one.
+func (e *viewEngine) Render(ctx contracts.Context, w io.Writer, name, layout string, data interface{}) error {
+	if ctx.Mode() == contracts.DevelopmentMode {
+		set, err := e.parse()
+		if err != nil {
+			return err
+		}
+
+		e.mutex.Lock()
+		e.set = set
+		e.mutex.Unlock()
+	}
+
+	if layout == "" {
+		layout = e.opt.Layout
+	}
+
+	e.mutex.RLock()
+	set, err := e.set.Clone() //executed templates can not be cloned, so the compiled set is never executed.
+	e.mutex.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	set.Funcs(e.helpers(ctx, set, name, data))
+
+	if set.Lookup(name) == nil {
+		return &contracts.HTTPError{Code: http.StatusInternalServerError, Message: fmt.Sprintf("view %q does not exist", name)}
+	}
+
+	if layout == "" {
+		return set.ExecuteTemplate(w, name, data)
+	}
+
+	if set.Lookup(layout) == nil {
+		return &contracts.HTTPError{Code: http.StatusInternalServerError, Message: fmt.Sprintf("layout %q does not exist", layout)}
+	}
+
+	return set.ExecuteTemplate(w, layout, data)
+}
+
+func (e *viewEngine) helpers(ctx contracts.Context, set *template.Template, name string, data interface{}) template.FuncMap {
+	funcs := template.FuncMap{}
+
+	for k, fn := range e.opt.Funcs {
+		funcs[k] = fn
+	}
+
+	funcs["yield"] = func() (template.HTML, error) {
+		buf := &bytes.Buffer{}
+		if err := set.ExecuteTemplate(buf, name, data); err != nil {
+			return "", err
+		}
+
+		return template.HTML(buf.String()), nil
+	}
+
+	funcs["url"] = func(route string, pairs ...interface{}) (string, error) {
+		r := ctx.Router().Named(route)
+		if r == nil {
+			return "", fmt.Errorf("route %q does not exist", route)
+		}
+
+		params := map[string]string{}
+		for i := 0; i+1 < len(pairs); i += 2 {
+			params[fmt.Sprint(pairs[i])] = fmt.Sprint(pairs[i+1])
+		}
+
+		return r.URL(params), nil
+	}
+
+	funcs["csrf_token"] = func() string {
+		return ctx.GetString(CSRFTokenKey)
+	}
+
+	funcs["csrf_field"] = func() template.HTML {
+		return template.HTML(`<input type="hidden" name="` + CSRFTokenKey + `" value="` +
+			template.HTMLEscapeString(ctx.GetString(CSRFTokenKey)) + `">`)
+	}
+
+	return funcs
+}
@@ -0,0 +1,416 @@
+package concretes
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-mango/mango/contracts"
+)
+
+const (
+	continuationFrame = 0
+
+	finalBit = 1 << 7
+	rsv1Bit  = 1 << 6
+	rsv2Bit  = 1 << 5
+	rsv3Bit  = 1 << 4
+	maskBit  = 1 << 7
+
+	maxControlPayload = 125
+)
+
+// deflateTail is removed from compressed messages and restored before
+// inflating them, as required by RFC 7692.
+var deflateTail = []byte{0x00, 0x00, 0xff, 0xff}
+
+// ErrWebSocketClosed is returned when using a connection after Close.
+var ErrWebSocketClosed = errors.New("websocket: use of closed connection")
+
+type webSocket struct {
+	ctx         contracts.Context
+	conn        net.Conn
+	br          *bufio.Reader
+	subprotocol string
+	compress    bool
+	readLimit   int64
+	pongWait    time.Duration
+	writeMutex  sync.Mutex
+	closeOnce   sync.Once
+	closed      chan struct{}
+}
+
+func newWebSocket(ctx contracts.Context, conn net.Conn, br *bufio.Reader, subprotocol string, compress bool, opt WebSocketOption) *webSocket {
+	if opt.ReadLimit <= 0 {
+		opt.ReadLimit = DefaultWebSocketReadLimit
+	}
+
+	ws := &webSocket{
+		ctx:         ctx,
+		conn:        conn,
+		br:          br,
+		subprotocol: subprotocol,
+		compress:    compress,
+		readLimit:   opt.ReadLimit,
+		closed:      make(chan struct{}),
+	}
+
+	if opt.PingInterval > 0 {
+		ws.pongWait = 2 * opt.PingInterval
+		go ws.keepalive(opt.PingInterval)
+	}
+
+	go func() {
+		select {
+		case <-ctx.Std().Done():
+			ws.Close(contracts.CloseGoingAway, "server shutting down")
+		case <-ws.closed:
+		}
+	}()
+
+	return ws
+}
+
+// Context returns context of the upgraded request.
+func (ws *webSocket) Context() contracts.Context {
+	return ws.ctx
+}
+
+// Subprotocol returns negotiated subprotocol.
+func (ws *webSocket) Subprotocol() string {
+	return ws.subprotocol
+}
+
+func (ws *webSocket) keepalive(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := ws.Ping(nil); err != nil {
+				return
+			}
+		case <-ws.closed:
+			return
+		}
+	}
+}
+
+// ReadMessage reads next data message, control frames are handled
+// in between: pings are answered and close frames are echoed.
+func (ws *webSocket) ReadMessage() (int, []byte, error) {
+	var (
+		messageType int
+		compressed  bool
+		payload     []byte
+	)
+
+	for {
+		if ws.pongWait > 0 {
+			ws.conn.SetReadDeadline(time.Now().Add(ws.pongWait))
+		}
+
+		fin, rsv1, opcode, data, err := ws.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch opcode {
+		case contracts.PingMessage:
+			if err := ws.writeFrame(contracts.PongMessage, data, false); err != nil {
+				return 0, nil, err
+			}
+
+			continue
+		case contracts.PongMessage:
+			continue
+		case contracts.CloseMessage:
+			return 0, nil, ws.handleClose(data)
+		case contracts.TextMessage, contracts.BinaryMessage:
+			if messageType != 0 {
+				return 0, nil, ws.fail(contracts.CloseProtocolError, "unexpected data frame")
+			}
+
+			messageType = opcode
+			compressed = rsv1
+		case continuationFrame:
+			if messageType == 0 {
+				return 0, nil, ws.fail(contracts.CloseProtocolError, "unexpected continuation frame")
+			}
+		default:
+			return 0, nil, ws.fail(contracts.CloseProtocolError, "unknown opcode")
+		}
+
+		if int64(len(payload)+len(data)) > ws.readLimit {
+			return 0, nil, ws.fail(contracts.CloseMessageTooBig, "message too big")
+		}
+
+		payload = append(payload, data...)
+
+		if !fin {
+			continue
+		}
+
+		if compressed {
+			if payload, err = ws.inflate(payload); err != nil {
+				return 0, nil, ws.fail(contracts.CloseInvalidFramePayloadData, "invalid compressed data")
+			}
+		}
+
+		if messageType == contracts.TextMessage && !utf8.Valid(payload) {
+			return 0, nil, ws.fail(contracts.CloseInvalidFramePayloadData, "invalid UTF-8 text")
+		}
+
+		return messageType, payload, nil
+	}
+}
+
+func (ws *webSocket) readFrame() (fin bool, rsv1 bool, opcode int, payload []byte, err error) {
+	var head [2]byte
+	if _, err = io.ReadFull(ws.br, head[:]); err != nil {
+		return
+	}
+
+	fin = head[0]&finalBit != 0
+	rsv1 = head[0]&rsv1Bit != 0
+	opcode = int(head[0] & 0x0f)
+
+	if head[0]&(rsv2Bit|rsv3Bit) != 0 || (rsv1 && !ws.compress) {
+		err = ws.fail(contracts.CloseProtocolError, "unexpected reserved bits")
+		return
+	}
+
+	if head[1]&maskBit == 0 {
+		err = ws.fail(contracts.CloseProtocolError, "client frames must be masked")
+		return
+	}
+
+	length := int64(head[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(ws.br, ext[:]); err != nil {
+			return
+		}
+
+		length = int64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(ws.br, ext[:]); err != nil {
+			return
+		}
+
+		length = int64(binary.BigEndian.Uint64(ext[:]))
+	}
+
+	if opcode >= contracts.CloseMessage && (length > maxControlPayload || !fin || rsv1) {
+		err = ws.fail(contracts.CloseProtocolError, "invalid control frame")
+		return
+	}
+
+	if length < 0 || length > ws.readLimit { //checked before allocating.
+		err = ws.fail(contracts.CloseMessageTooBig, "message too big")
+		return
+	}
+
+	var mask [4]byte
+	if _, err = io.ReadFull(ws.br, mask[:]); err != nil {
+		return
+	}
+
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(ws.br, payload); err != nil {
+		return
+	}
+
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	return
+}
+
+func (ws *webSocket) handleClose(data []byte) error {
+	code := contracts.CloseNoStatusReceived
+	reason := ""
+
+	if len(data) == 1 {
+		ws.fail(contracts.CloseProtocolError, "invalid close frame")
+		return &contracts.CloseError{Code: contracts.CloseProtocolError}
+	}
+
+	if len(data) >= 2 {
+		code = int(binary.BigEndian.Uint16(data))
+		reason = string(data[2:])
+
+		if !validCloseCode(code) || !utf8.ValidString(reason) {
+			ws.fail(contracts.CloseProtocolError, "invalid close frame")
+			return &contracts.CloseError{Code: code, Reason: reason}
+		}
+	}
+
+	echo := code
+	if code == contracts.CloseNoStatusReceived {
+		echo = contracts.CloseNormalClosure
+	}
+
+	ws.Close(echo, "")
+
+	return &contracts.CloseError{Code: code, Reason: reason}
+}
+
+func validCloseCode(code int) bool {
+	switch code {
+	case 1004, contracts.CloseNoStatusReceived, contracts.CloseAbnormalClosure, 1015:
+		return false
+	}
+
+	return (code >= 1000 && code <= 1014) || (code >= 3000 && code <= 4999)
+}
+
+// fail closes connection because of a protocol violation by the peer.
+func (ws *webSocket) fail(code int, reason string) error {
+	ws.Close(code, reason)
+	return &contracts.CloseError{Code: code, Reason: reason}
+}
+
+func (ws *webSocket) inflate(payload []byte) ([]byte, error) {
+	r := flate.NewReader(io.MultiReader(
+		bytes.NewReader(payload),
+		bytes.NewReader(deflateTail),
+		bytes.NewReader([]byte{0x01, 0x00, 0x00, 0xff, 0xff}),
+	))
+	defer r.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(r, ws.readLimit+1))
+	if err == nil && int64(len(data)) > ws.readLimit {
+		return nil, errors.New("websocket: message too big")
+	}
+
+	return data, err
+}
+
+func deflate(payload []byte) ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	w, err := flate.NewWriter(buf, flate.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := w.Write(payload); err != nil {
+		return nil, err
+	}
+
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), deflateTail), nil
+}
+
+// WriteMessage writes a single frame message.
+func (ws *webSocket) WriteMessage(messageType int, data []byte) error {
+	if messageType != contracts.TextMessage && messageType != contracts.BinaryMessage {
+		return errors.New("websocket: invalid message type")
+	}
+
+	return ws.writeFrame(messageType, data, ws.compress)
+}
+
+func (ws *webSocket) writeFrame(opcode int, payload []byte, compress bool) error {
+	select {
+	case <-ws.closed:
+		return ErrWebSocketClosed
+	default:
+	}
+
+	return ws.writeRaw(opcode, payload, compress)
+}
+
+func (ws *webSocket) writeRaw(opcode int, payload []byte, compress bool) error {
+	head := []byte{finalBit | byte(opcode), 0}
+
+	if compress {
+		var err error
+		if payload, err = deflate(payload); err != nil {
+			return err
+		}
+
+		head[0] |= rsv1Bit
+	}
+
+	n := len(payload)
+	switch {
+	case n <= maxControlPayload:
+		head[1] = byte(n)
+	case n <= 0xffff:
+		head[1] = 126
+		head = appendUint16(head, uint16(n))
+	default:
+		head[1] = 127
+		head = appendUint64(head, uint64(n))
+	}
+
+	ws.writeMutex.Lock()
+	defer ws.writeMutex.Unlock()
+
+	_, err := (&net.Buffers{head, payload}).WriteTo(ws.conn)
+	return err
+}
+
+// ReadJSON reads next message and decodes it as JSON.
+func (ws *webSocket) ReadJSON(v interface{}) error {
+	_, data, err := ws.ReadMessage()
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// WriteJSON encodes v as JSON and writes it as a text message.
+func (ws *webSocket) WriteJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return ws.WriteMessage(contracts.TextMessage, data)
+}
+
+// Ping sends a ping frame, pongs are consumed by ReadMessage.
+func (ws *webSocket) Ping(data []byte) error {
+	return ws.writeFrame(contracts.PingMessage, data, false)
+}
+
+// Close sends close frame with given code and closes the connection.
+func (ws *webSocket) Close(code int, reason string) error {
+	err := ErrWebSocketClosed
+
+	ws.closeOnce.Do(func() {
+		payload := appendUint16(nil, uint16(code))
+		payload = append(payload, reason...)
+		if len(payload) > maxControlPayload {
+			payload = payload[:maxControlPayload]
+		}
+
+		ws.conn.SetWriteDeadline(time.Now().Add(time.Second))
+		err = ws.writeRaw(contracts.CloseMessage, payload, false)
+
+		close(ws.closed)
+		ws.conn.Close()
+	})
+
+	return err
+}
@@ -0,0 +1,272 @@
+package concretes
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+
+	"github.com/go-mango/mango/contracts"
+)
+
+// pipeWebSocket connects server side of a pipe as webSocket,
+// the client side is returned along.
+func pipeWebSocket(compress bool, limit int64) (*webSocket, net.Conn) {
+	server, client := net.Pipe()
+	ws := newWebSocket(newTestContext("GET", "/ws", nil), server, bufio.NewReader(server), "", compress, WebSocketOption{ReadLimit: limit})
+
+	return ws, client
+}
+
+// clientFrame builds a frame as sent by clients, masked unless told otherwise.
+func clientFrame(head byte, payload []byte, unmasked bool) []byte {
+	b := []byte{head, 0}
+
+	n := len(payload)
+	switch {
+	case n <= maxControlPayload:
+		b[1] = byte(n)
+	case n <= 0xffff:
+		b[1] = 126
+		b = appendUint16(b, uint16(n))
+	default:
+		b[1] = 127
+		b = appendUint64(b, uint64(n))
+	}
+
+	if unmasked {
+		return append(b, payload...)
+	}
+
+	b[1] |= maskBit
+	mask := []byte{0x12, 0x34, 0x56, 0x78}
+	b = append(b, mask...)
+
+	for i, c := range payload {
+		b = append(b, c^mask[i%4])
+	}
+
+	return b
+}
+
+// readServerFrame reads an unmasked frame sent by server.
+func readServerFrame(r io.Reader) (byte, []byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return 0, nil, err
+	}
+
+	n := uint64(head[1] & 0x7f)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+
+	payload := make([]byte, n)
+	_, err := io.ReadFull(r, payload)
+
+	return head[0], payload, err
+}
+
+func compressed(t *testing.T, payload []byte) []byte {
+	b, err := deflate(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+func TestWebSocketRoundTrip(t *testing.T) {
+	ws, client := pipeWebSocket(false, 0)
+	defer ws.Close(contracts.CloseNormalClosure, "")
+	defer client.Close()
+
+	if ws.readLimit != DefaultWebSocketReadLimit {
+		t.Errorf("readLimit = %d, want default %d", ws.readLimit, DefaultWebSocketReadLimit)
+	}
+
+	big := bytes.Repeat([]byte("x"), 70000)
+
+	go func() {
+		client.Write(clientFrame(finalBit|contracts.TextMessage, []byte("hello"), false))
+		client.Write(clientFrame(contracts.BinaryMessage, big[:40000], false))
+		client.Write(clientFrame(finalBit|contracts.PingMessage, []byte("p"), false))
+		client.Write(clientFrame(finalBit|continuationFrame, big[40000:], false))
+	}()
+
+	pong := make(chan []byte, 1)
+
+	for _, want := range []struct {
+		messageType int
+		data        []byte
+	}{
+		{contracts.TextMessage, []byte("hello")},
+		{contracts.BinaryMessage, big},
+	} {
+		if want.messageType == contracts.BinaryMessage {
+			go func() {
+				_, payload, _ := readServerFrame(client)
+				pong <- payload
+			}()
+		}
+
+		messageType, data, err := ws.ReadMessage()
+		if err != nil || messageType != want.messageType || !bytes.Equal(data, want.data) {
+			t.Fatalf("ReadMessage() = %d, %.10q, %v, want %d, %.10q", messageType, data, err, want.messageType, want.data)
+		}
+	}
+
+	if p := <-pong; string(p) != "p" {
+		t.Errorf("pong payload = %q, want %q", p, "p")
+	}
+
+	for _, data := range [][]byte{[]byte("hi"), big} {
+		go ws.WriteMessage(contracts.BinaryMessage, data)
+
+		head, payload, err := readServerFrame(client)
+		if err != nil || head != finalBit|contracts.BinaryMessage || !bytes.Equal(payload, data) {
+			t.Errorf("server frame = %x, %.10q, %v", head, payload, err)
+		}
+	}
+}
+
+func TestWebSocketCompressedRoundTrip(t *testing.T) {
+	ws, client := pipeWebSocket(true, 0)
+	defer ws.Close(contracts.CloseNormalClosure, "")
+	defer client.Close()
+
+	message := bytes.Repeat([]byte("compressible "), 100)
+
+	go client.Write(clientFrame(finalBit|rsv1Bit|contracts.TextMessage, compressed(t, message), false))
+
+	if _, data, err := ws.ReadMessage(); err != nil || !bytes.Equal(data, message) {
+		t.Fatalf("ReadMessage() = %.10q, %v", data, err)
+	}
+
+	go ws.WriteMessage(contracts.TextMessage, message)
+
+	head, payload, err := readServerFrame(client)
+	if err != nil || head&rsv1Bit == 0 {
+		t.Fatalf("server frame = %x, %v, want compressed", head, err)
+	}
+
+	data, err := ioutil.ReadAll(flate.NewReader(io.MultiReader(bytes.NewReader(payload), bytes.NewReader(deflateTail))))
+	if err != io.ErrUnexpectedEOF && err != nil || !bytes.Equal(data, message) {
+		t.Errorf("inflated = %.10q, %v", data, err)
+	}
+}
+
+func TestWebSocketMalformedFrames(t *testing.T) {
+	text := finalBit | byte(contracts.TextMessage)
+	ping := finalBit | byte(contracts.PingMessage)
+	closing := finalBit | byte(contracts.CloseMessage)
+	bomb := bytes.Repeat([]byte{0}, 4096)
+
+	for _, tt := range []struct {
+		name     string
+		compress bool
+		limit    int64
+		frames   [][]byte
+		code     int
+	}{
+		{"unmasked", false, 0, [][]byte{clientFrame(text, []byte("a"), true)}, contracts.CloseProtocolError},
+		{"rsv2", false, 0, [][]byte{clientFrame(text|rsv2Bit, []byte("a"), false)}, contracts.CloseProtocolError},
+		{"rsv1 without extension", false, 0, [][]byte{clientFrame(text|rsv1Bit, []byte("a"), false)}, contracts.CloseProtocolError},
+		{"compressed control frame", true, 0, [][]byte{clientFrame(ping|rsv1Bit, []byte("a"), false)}, contracts.CloseProtocolError},
+		{"fragmented control frame", false, 0, [][]byte{clientFrame(contracts.PingMessage, []byte("a"), false)}, contracts.CloseProtocolError},
+		{"long control frame", false, 0, [][]byte{clientFrame(ping, make([]byte, 126), false)}, contracts.CloseProtocolError},
+		{"unknown opcode", false, 0, [][]byte{clientFrame(finalBit|3, nil, false)}, contracts.CloseProtocolError},
+		{"orphan continuation", false, 0, [][]byte{clientFrame(finalBit|continuationFrame, []byte("a"), false)}, contracts.CloseProtocolError},
+		{"interleaved data frames", false, 0, [][]byte{
+			clientFrame(contracts.TextMessage, []byte("a"), false),
+			clientFrame(text, []byte("b"), false),
+		}, contracts.CloseProtocolError},
+		{"invalid UTF-8", false, 0, [][]byte{clientFrame(text, []byte{0xff, 0xfe}, false)}, contracts.CloseInvalidFramePayloadData},
+		{"invalid compressed data", true, 0, [][]byte{clientFrame(text|rsv1Bit, []byte{0xff, 0xff, 0xff}, false)}, contracts.CloseInvalidFramePayloadData},
+		{"huge length", false, 0, [][]byte{{text, maskBit | 127, 0, 0, 1, 0, 0, 0, 0, 0}}, contracts.CloseMessageTooBig},
+		{"frame over limit", false, 8, [][]byte{clientFrame(text, make([]byte, 9), false)}, contracts.CloseMessageTooBig},
+		{"fragments over limit", false, 8, [][]byte{
+			clientFrame(contracts.TextMessage, make([]byte, 5), false),
+			clientFrame(finalBit|continuationFrame, make([]byte, 5), false),
+		}, contracts.CloseMessageTooBig},
+		{"inflated over limit", true, 1024, [][]byte{clientFrame(text|rsv1Bit, compressed(t, bomb), false)}, contracts.CloseInvalidFramePayloadData},
+		{"one byte close", false, 0, [][]byte{clientFrame(closing, []byte{0x03}, false)}, contracts.CloseProtocolError},
+		{"reserved close code", false, 0, [][]byte{clientFrame(closing, appendUint16(nil, uint16(contracts.CloseNoStatusReceived)), false)}, contracts.CloseProtocolError},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ws, client := pipeWebSocket(tt.compress, tt.limit)
+			defer client.Close()
+
+			go func() {
+				for _, f := range tt.frames {
+					if _, err := client.Write(f); err != nil {
+						return
+					}
+				}
+			}()
+
+			sent := make(chan int, 1)
+			go func() {
+				_, payload, err := readServerFrame(client)
+				if err != nil || len(payload) < 2 {
+					sent <- 0
+					return
+				}
+
+				sent <- int(binary.BigEndian.Uint16(payload))
+				io.Copy(ioutil.Discard, client)
+			}()
+
+			_, _, err := ws.ReadMessage()
+
+			if _, ok := err.(*contracts.CloseError); !ok {
+				t.Fatalf("ReadMessage() error = %v, want *contracts.CloseError", err)
+			}
+
+			if code := <-sent; code != tt.code {
+				t.Errorf("close frame sent with %d, want %d", code, tt.code)
+			}
+		})
+	}
+}
+
+func TestWebSocketClose(t *testing.T) {
+	ws, client := pipeWebSocket(false, 0)
+	defer client.Close()
+
+	go client.Write(clientFrame(finalBit|contracts.CloseMessage, append(appendUint16(nil, uint16(contracts.CloseGoingAway)), "bye"...), false))
+
+	echo := make(chan []byte, 1)
+	go func() {
+		_, payload, _ := readServerFrame(client)
+		echo <- payload
+	}()
+
+	_, _, err := ws.ReadMessage()
+	if ce, ok := err.(*contracts.CloseError); !ok || ce.Code != contracts.CloseGoingAway || ce.Reason != "bye" {
+		t.Fatalf("ReadMessage() error = %v", err)
+	}
+
+	if p := <-echo; len(p) < 2 || binary.BigEndian.Uint16(p) != contracts.CloseGoingAway {
+		t.Errorf("echoed close payload = %x", p)
+	}
+
+	if err := ws.WriteMessage(contracts.TextMessage, []byte("late")); err != ErrWebSocketClosed {
+		t.Errorf("WriteMessage after close = %v, want ErrWebSocketClosed", err)
+	}
+}
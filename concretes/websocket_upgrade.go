@@ -0,0 +1,182 @@
+package concretes
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-mango/mango/contracts"
+)
+
+// websocketGUID is the magic value of RFC 6455 handshake.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// DefaultWebSocketReadLimit limits incoming messages when
+// WebSocketOption.ReadLimit is not set.
+const DefaultWebSocketReadLimit = 32 << 20
+
+// WebSocketOption configures WebSocket upgrades.
+type WebSocketOption struct {
+	// AllowedOrigins lists origins allowed to connect, "*" allows any.
+	// Only same origin requests are allowed when it is empty.
+	AllowedOrigins []string
+	// CheckOrigin replaces AllowedOrigins when it is set.
+	CheckOrigin func(contracts.Request) bool
+	// Subprotocols supported by server in order of preference.
+	Subprotocols []string
+	// DisableCompression turns off permessage-deflate negotiation.
+	DisableCompression bool
+	// ReadLimit limits size of incoming messages, it defaults to
+	// DefaultWebSocketReadLimit.
+	ReadLimit int64
+	// PingInterval enables keepalive pings, connections not heard from
+	// within two intervals are considered dead.
+	PingInterval time.Duration
+}
+
+// Upgrade performs RFC 6455 handshake and takes over the connection,
+// handshake failures are returned as *contracts.HTTPError.
+func Upgrade(ctx contracts.Context, opt WebSocketOption) (contracts.WebSocket, error) {
+	r := ctx.Request()
+
+	if r.Method() != http.MethodGet ||
+		!headerContains(r.Header(), "Connection", "upgrade") ||
+		!headerContains(r.Header(), "Upgrade", "websocket") {
+		return nil, &contracts.HTTPError{Code: http.StatusBadRequest, Message: "not a websocket handshake"}
+	}
+
+	if r.Header().Get("Sec-WebSocket-Version") != "13" {
+		ctx.Response().Header().Set("Sec-WebSocket-Version", "13")
+		return nil, &contracts.HTTPError{Code: http.StatusUpgradeRequired, Message: "unsupported websocket version"}
+	}
+
+	key := r.Header().Get("Sec-WebSocket-Key")
+	if raw, err := base64.StdEncoding.DecodeString(key); err != nil || len(raw) != 16 {
+		return nil, &contracts.HTTPError{Code: http.StatusBadRequest, Message: "invalid Sec-WebSocket-Key"}
+	}
+
+	if !checkOrigin(r, opt) {
+		return nil, &contracts.HTTPError{Code: http.StatusForbidden, Message: "origin not allowed"}
+	}
+
+	subprotocol := selectSubprotocol(r.Header(), opt.Subprotocols)
+	compress := !opt.DisableCompression && offersDeflate(r.Header())
+
+	conn, rw, err := ctx.Response().Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha1.Sum([]byte(key + websocketGUID))
+
+	handshake := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n"
+
+	if subprotocol != "" {
+		handshake += "Sec-WebSocket-Protocol: " + subprotocol + "\r\n"
+	}
+
+	if compress {
+		handshake += "Sec-WebSocket-Extensions: permessage-deflate; server_no_context_takeover; client_no_context_takeover\r\n"
+	}
+
+	if id := ctx.RequestID(); id != "" {
+		handshake += RequestIDHeader + ": " + id + "\r\n"
+	}
+
+	conn.SetDeadline(time.Time{})
+	if _, err := conn.Write([]byte(handshake + "\r\n")); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	ctx.Response().SetStatus(http.StatusSwitchingProtocols)
+
+	return newWebSocket(ctx, conn, rw.Reader, subprotocol, compress, opt), nil
+}
+
+// WebSocketHandler creates route handler that upgrades requests and
+// hands connections to fn, route middlewares run before the upgrade.
+func WebSocketHandler(fn contracts.WebSocketHandler, opt WebSocketOption) contracts.Callable {
+	return func(ctx contracts.Context) (int, interface{}) {
+		ws, err := Upgrade(ctx, opt)
+		if err != nil {
+			return 0, err
+		}
+
+		defer ws.Close(contracts.CloseNormalClosure, "")
+
+		fn(ws)
+
+		return 0, nil
+	}
+}
+
+func headerContains(h http.Header, name, token string) bool {
+	for _, v := range h[name] {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func checkOrigin(r contracts.Request, opt WebSocketOption) bool {
+	if opt.CheckOrigin != nil {
+		return opt.CheckOrigin(r)
+	}
+
+	origin := r.Header().Get("Origin")
+	if origin == "" {
+		return true //non-browser clients.
+	}
+
+	if len(opt.AllowedOrigins) == 0 {
+		u, err := url.Parse(origin)
+		return err == nil && strings.EqualFold(u.Host, r.Host())
+	}
+
+	for _, allowed := range opt.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func selectSubprotocol(h http.Header, supported []string) string {
+	for _, v := range h["Sec-Websocket-Protocol"] {
+		for _, p := range strings.Split(v, ",") {
+			p = strings.TrimSpace(p)
+			for _, s := range supported {
+				if p == s {
+					return p
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+func offersDeflate(h http.Header) bool {
+	for _, v := range h["Sec-Websocket-Extensions"] {
+		for _, ext := range strings.Split(v, ",") {
+			name := strings.TrimSpace(strings.Split(ext, ";")[0])
+			if name == "permessage-deflate" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
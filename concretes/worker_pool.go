@@ -0,0 +1,89 @@
+package concretes
+
+import (
+	"runtime/debug"
+	"sync"
+
+	"github.com/go-mango/logy"
+)
+
+// WorkerPool runs tasks in background with a fixed number of
+// goroutines, tasks are dropped when the queue is full.
+type WorkerPool struct {
+	workers int
+	tasks   chan func()
+	start   sync.Once
+	stop    sync.Once
+	wg      sync.WaitGroup
+	mutex   sync.RWMutex
+	closed  bool
+}
+
+// NewWorkerPool creates pool of given size, workers are started
+// when the first task is submitted.
+func NewWorkerPool(workers, queue int) *WorkerPool {
+	if workers < 1 {
+		workers = 1
+	}
+
+	return &WorkerPool{
+		workers: workers,
+		tasks:   make(chan func(), queue),
+	}
+}
+
+// Submit queues task, false is returned when it is dropped because
+// the queue is full or the pool is closed.
+func (p *WorkerPool) Submit(task func()) bool {
+	p.start.Do(func() {
+		for i := 0; i < p.workers; i++ {
+			p.wg.Add(1)
+			go p.work()
+		}
+	})
+
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	if p.closed {
+		return false
+	}
+
+	select {
+	case p.tasks <- task:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close stops accepting tasks and waits for queued ones to finish.
+func (p *WorkerPool) Close() {
+	p.stop.Do(func() {
+		p.mutex.Lock()
+		p.closed = true
+		close(p.tasks)
+		p.mutex.Unlock()
+	})
+
+	p.wg.Wait()
+}
+
+func (p *WorkerPool) work() {
+	defer p.wg.Done()
+
+	for task := range p.tasks {
+		safeRun(task)
+	}
+}
+
+// safeRun calls task, panics are logged instead of crashing the server.
+func safeRun(task func()) {
+	defer func() {
+		if err := recover(); err != nil {
+			logy.Std().Warnf("WORKER: panic: %v\n%s", err, debug.Stack())
+		}
+	}()
+
+	task()
+}
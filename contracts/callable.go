@@ -2,3 +2,20 @@ package contracts
 
 // Callable use to handle incoming requests.
 type Callable func(Context) (int, interface{})
+
+// Handler is any of supported handler forms, it is adapted
+// to Callable once at route registration:
+//
+//	func(Context) (int, interface{})
+//	func(Context) error
+//	func(Context) (interface{}, error)
+//	func(Context, *Input) (*Output, error)
+//	http.Handler, http.HandlerFunc and func(http.ResponseWriter, *http.Request)
+//
+// *Input is bound from request and validated before the call.
+type Handler interface{}
+
+// Validatable is implemented by bound inputs that validate themselves.
+type Validatable interface {
+	Validate() error
+}
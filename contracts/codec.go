@@ -0,0 +1,11 @@
+package contracts
+
+import (
+	"io"
+)
+
+// Codec encodes response values of a media type.
+type Codec interface {
+	ContentType() string
+	Encode(io.Writer, interface{}) error
+}
@@ -1,5 +1,11 @@
 package contracts
 
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
 //Context represents incoming connection.
 type Context interface {
 	Request() Request
@@ -7,5 +13,33 @@ type Context interface {
 	Auth() Authenable
 	URL(string, map[string]string) string
 	Cache() Cachable
+	Mode() string
+	Router() Router
+	Route() Route
+	View(string, interface{}) Renderable
 	Session() Session
+	Set(string, interface{})
+	Get(string) (interface{}, bool)
+	MustGet(string) interface{}
+	GetString(string) string
+	GetInt(string) int
+	GetInt64(string) int64
+	GetFloat64(string) float64
+	GetBool(string) bool
+	RequestID() string
+	Error(error)
+	Transform(Transformer)
+	OnAfterResponse(func(Context))
+	Timing(string, time.Duration)
+	SSE(func(EventStream) error) (int, interface{})
+	CheckPreconditions(Version, bool) error
+	SetVersion(Version)
+	Std() context.Context
+	SetStd(context.Context)
+	WithValue(key, value interface{})
+	Keyring() Keyring
+	SetSignedCookie(*http.Cookie)
+	SignedCookie(string) (string, error)
+	SetEncryptedCookie(*http.Cookie) error
+	EncryptedCookie(string) (string, error)
 }
@@ -0,0 +1,30 @@
+package contracts
+
+// ErrorHandler decides how errors of incoming requests are logged and rendered.
+type ErrorHandler func(Context, error)
+
+// HTTPError is an error with HTTP status code, handlers may return it
+// as response value to reply with a structured error.
+type HTTPError struct {
+	Code    int
+	Message string
+	Details interface{}
+	Err     error
+}
+
+// Error returns message of the error.
+func (e *HTTPError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+
+	return e.Message
+}
+
+// Unwrap returns underlying error.
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorRenderer renders errors as pages for browsers.
+type ErrorRenderer func(Context, *HTTPError) error
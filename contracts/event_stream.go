@@ -0,0 +1,27 @@
+package contracts
+
+import (
+	"time"
+)
+
+// Event is a Server-Sent Event, Data is written as is when it is
+// a string or []byte, other values are JSON encoded.
+type Event struct {
+	ID    string
+	Event string
+	Data  interface{}
+	Retry time.Duration
+}
+
+// EventStream sends Server-Sent Events to client.
+type EventStream interface {
+	Send(Event) error
+	LastEventID() string
+	Done() <-chan struct{}
+}
+
+// EventHub broadcasts events to streams subscribed to topics.
+type EventHub interface {
+	Subscribe(topic string, stream EventStream) func()
+	Publish(topic string, event Event)
+}
@@ -0,0 +1,10 @@
+package contracts
+
+// Iterator yields values one at a time, handlers return it to stream
+// large results without loading them into memory. It is closed after
+// streaming when it implements io.Closer.
+type Iterator interface {
+	Next() bool
+	Value() interface{}
+	Err() error
+}
@@ -0,0 +1,44 @@
+package contracts
+
+import (
+	"io"
+)
+
+// JSONEngine creates JSON encoders and decoders, it allows replacing
+// encoding/json with a compatible library.
+type JSONEngine interface {
+	NewEncoder(io.Writer) JSONEncoder
+	NewDecoder(io.Reader) JSONDecoder
+}
+
+// JSONEncoder is implemented by *json.Encoder.
+type JSONEncoder interface {
+	Encode(interface{}) error
+	SetEscapeHTML(bool)
+	SetIndent(prefix, indent string)
+}
+
+// JSONDecoder is implemented by *json.Decoder.
+type JSONDecoder interface {
+	Decode(interface{}) error
+	DisallowUnknownFields()
+	UseNumber()
+}
+
+// JSONOption configures how an application reads and writes JSON.
+type JSONOption struct {
+	// Engine defaults to encoding/json.
+	Engine JSONEngine
+	// DisallowUnknownFields rejects request bodies with unknown fields.
+	DisallowUnknownFields bool
+	// UseNumber decodes numbers in interface{} as json.Number.
+	UseNumber bool
+	// DisableHTMLEscape stops escaping <, > and & in strings.
+	DisableHTMLEscape bool
+	// PrettyParam is query parameter that indents responses in
+	// development mode, defaults to "pretty".
+	PrettyParam string
+	// JSONPParam is query parameter carrying JSONP callback name,
+	// JSONP is disabled when it is empty.
+	JSONPParam string
+}
@@ -0,0 +1,12 @@
+package contracts
+
+// Keyring signs and encrypts values with a set of rotatable keys,
+// the newest key is used for new values while older ones are
+// still accepted for verification and decryption.
+type Keyring interface {
+	Rotate([]byte)
+	Sign(name string, value string) string
+	Verify(name string, signed string) (string, error)
+	Encrypt(name string, value string) (string, error)
+	Decrypt(name string, sealed string) (string, error)
+}
@@ -8,18 +8,26 @@ import (
 
 // Mango interface of mango micro framework.
 type Mango interface {
-	Any(string, Callable, ...ThenableFunc)
-	Get(string, Callable, ...ThenableFunc)
-	Post(string, Callable, ...ThenableFunc)
-	Put(string, Callable, ...ThenableFunc)
-	Delete(string, Callable, ...ThenableFunc)
+	Any(string, Handler, ...ThenableFunc) Route
+	Get(string, Handler, ...ThenableFunc) Route
+	Post(string, Handler, ...ThenableFunc) Route
+	Put(string, Handler, ...ThenableFunc) Route
+	Delete(string, Handler, ...ThenableFunc) Route
+	WebSocket(string, WebSocketHandler, ...ThenableFunc) Route
 	Group(string, func(Router), ...ThenableFunc)
 	Use(ThenableFunc)
-	SetDefaultRoute(Callable)
+	SetDefaultRoute(Handler)
 	SetCachable(Cachable)
+	SetKeyring(Keyring)
+	SetMode(string)
+	SetViewEngine(ViewEngine)
+	SetJSON(JSONOption)
+	TrustProxies(...string)
 	Start(string)
 	StartTLS(string, string, string)
 	StartAutoTLS(string, autocert.Cache, ...string)
 	ServeHTTP(http.ResponseWriter, *http.Request)
 	On(event string, fn func())
+	OnError(ErrorHandler)
+	SetErrorRenderer(int, ErrorRenderer)
 }
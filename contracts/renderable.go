@@ -0,0 +1,10 @@
+package contracts
+
+// Renderable is implemented by return values that write themselves
+// to response instead of being encoded by codecs.
+type Renderable interface {
+	Render(Context) error
+}
+
+// RenderFunc renders return values of a registered type.
+type RenderFunc func(Context, interface{}) error
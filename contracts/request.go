@@ -12,6 +12,7 @@ type Request interface {
 	File(string) (UploadedFile, error)
 	Form(string) string
 	Query(string) string
+	Cookie(string) string
 	Arg(string) string
 	Input(string) string
 	JSON(interface{}) error
@@ -23,4 +24,6 @@ type Request interface {
 	Host() string
 	SetArgs(map[string]string)
 	Args() map[string]string
+	ID() string
+	SetID(string)
 }
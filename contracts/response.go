@@ -1,6 +1,9 @@
 package contracts
 
 import (
+	"bufio"
+	"io"
+	"net"
 	"net/http"
 )
 
@@ -17,7 +20,15 @@ type Response interface {
 	SetStatus(int)
 	SetCookie(*http.Cookie)
 	DelCookie(string)
+	ExpireCookie(*http.Cookie)
 	Redirect(int, string) (int, interface{})
 	Buffered() []byte
 	Send() error
+	OnBeforeSend(func(Response))
+	Stream(func(io.Writer) error) error
+	Flush()
+	IsStreaming() bool
+	RequireBuffering()
+	Committed() bool
+	Hijack() (net.Conn, *bufio.ReadWriter, error)
 }
@@ -13,4 +13,7 @@ type Route interface {
 	Callable() Callable
 	ThenStack() []ThenableFunc
 	IsStatic() bool
+	Name() string
+	SetName(string) Route
+	URL(map[string]string) string
 }
@@ -2,16 +2,18 @@ package contracts
 
 // Router interface.
 type Router interface {
-	Any(string, Callable, ...ThenableFunc)
-	Get(string, Callable, ...ThenableFunc)
-	Post(string, Callable, ...ThenableFunc)
-	Put(string, Callable, ...ThenableFunc)
-	Delete(string, Callable, ...ThenableFunc)
+	Any(string, Handler, ...ThenableFunc) Route
+	Get(string, Handler, ...ThenableFunc) Route
+	Post(string, Handler, ...ThenableFunc) Route
+	Put(string, Handler, ...ThenableFunc) Route
+	Delete(string, Handler, ...ThenableFunc) Route
+	WebSocket(string, WebSocketHandler, ...ThenableFunc) Route
 	Group(string, func(Router), ...ThenableFunc)
 	Use(...ThenableFunc)
 	Prefixes() []string
 	ThenableStack() []ThenableFunc
 	SetThenableStack(...ThenableFunc)
 	ToMatch(Request) (Route, map[string]string)
-	SetDefaultRoute(Callable)
+	SetDefaultRoute(Handler)
+	Named(string) Route
 }
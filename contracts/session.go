@@ -1,7 +1,24 @@
 package contracts
 
-// Session interface.
+import (
+	"time"
+)
+
+// Session is server side storage that survives between requests.
 type Session interface {
-	Set(string, interface{})
+	ID() string
 	Get(string) (interface{}, bool)
+	Set(string, interface{})
+	Delete(string)
+	Flush()
+	Regenerate()
+	Flash(string, interface{})
+	Flashed(string) (interface{}, bool)
+}
+
+// SessionStore loads and saves encoded session data.
+type SessionStore interface {
+	Load(ctx Context, id string) ([]byte, error)
+	Save(ctx Context, id string, data []byte, ttl time.Duration) error
+	Destroy(ctx Context, id string) error
 }
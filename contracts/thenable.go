@@ -7,4 +7,9 @@ type ThenableFunc func(ThenableContext)
 type ThenableContext interface {
 	Context
 	Next()
+	Abort()
+	AbortWithStatus(int)
+	AbortWith(int, interface{})
+	IsAborted() bool
+	SetSession(Session)
 }
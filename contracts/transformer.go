@@ -0,0 +1,6 @@
+package contracts
+
+// Transformer reshapes status code and value returned by handler right
+// before it is encoded, Content-Type of response is already negotiated
+// when it runs. Returned error is rendered instead of the value.
+type Transformer func(Context, int, interface{}) (int, interface{}, error)
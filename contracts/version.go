@@ -0,0 +1,17 @@
+package contracts
+
+import (
+	"time"
+)
+
+// Version identifies current state of a resource for preconditions,
+// the zero Version stands for a resource that does not exist.
+type Version struct {
+	ETag     string
+	Modified time.Time
+}
+
+// Exists reports whether version stands for an existing resource.
+func (v Version) Exists() bool {
+	return v.ETag != "" || !v.Modified.IsZero()
+}
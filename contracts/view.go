@@ -0,0 +1,19 @@
+package contracts
+
+import (
+	"io"
+)
+
+// Modes of application, development mode trades speed for convenience
+// such as reloading templates on every render.
+const (
+	DevelopmentMode = "development"
+	ProductionMode  = "production"
+)
+
+// ViewEngine renders named templates.
+type ViewEngine interface {
+	// Render writes template name wrapped in layout to w,
+	// empty layout falls back to the default one of engine.
+	Render(ctx Context, w io.Writer, name, layout string, data interface{}) error
+}
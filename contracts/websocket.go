@@ -0,0 +1,56 @@
+package contracts
+
+import (
+	"strconv"
+)
+
+// Message types of WebSocket frames defined by RFC 6455.
+const (
+	TextMessage   = 1
+	BinaryMessage = 2
+	CloseMessage  = 8
+	PingMessage   = 9
+	PongMessage   = 10
+)
+
+// Close codes of WebSocket connections defined by RFC 6455.
+const (
+	CloseNormalClosure           = 1000
+	CloseGoingAway               = 1001
+	CloseProtocolError           = 1002
+	CloseUnsupportedData         = 1003
+	CloseNoStatusReceived        = 1005
+	CloseAbnormalClosure         = 1006
+	CloseInvalidFramePayloadData = 1007
+	ClosePolicyViolation         = 1008
+	CloseMessageTooBig           = 1009
+	CloseMandatoryExtension      = 1010
+	CloseInternalServerErr       = 1011
+)
+
+// WebSocket is an upgraded RFC 6455 connection.
+type WebSocket interface {
+	Context() Context
+	Subprotocol() string
+	ReadMessage() (int, []byte, error)
+	WriteMessage(int, []byte) error
+	ReadJSON(interface{}) error
+	WriteJSON(interface{}) error
+	Ping([]byte) error
+	Close(int, string) error
+}
+
+// WebSocketHandler handles upgraded connections, the connection
+// is closed when it returns.
+type WebSocketHandler func(WebSocket)
+
+// CloseError is returned by reads once the peer closed the connection.
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+// Error returns description of the close frame.
+func (e *CloseError) Error() string {
+	return "websocket: closed with code " + strconv.Itoa(e.Code) + " " + e.Reason
+}
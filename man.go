@@ -3,9 +3,11 @@ package mango
 import (
 	"context"
 	"crypto/tls"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"runtime"
 	"time"
 
 	"github.com/go-mango/logy"
@@ -19,8 +21,11 @@ import (
 type mango struct {
 	router    contracts.Router
 	thenStack []contracts.ThenableFunc
-	cache     contracts.Cachable
+	services  *concretes.Services
 	events    map[string][]func()
+	trusted   []*net.IPNet
+	base      context.Context
+	cancel    context.CancelFunc
 }
 
 func (m *mango) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -29,23 +34,60 @@ func (m *mango) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	route, params := m.router.ToMatch(request)
 	request.SetArgs(params)
-	thenStack := append(m.thenStack, route.ThenStack()...)
+	request.SetID(concretes.ResolveRequestID(request, m.trusted))
+	response.Header().Set(concretes.RequestIDHeader, request.ID())
+	thenStack := make([]contracts.ThenableFunc, 0, len(m.thenStack)+len(route.ThenStack())+1)
+	thenStack = append(append(thenStack, m.thenStack...), route.ThenStack()...)
 
 	ctx := concretes.NewContext(
 		request,
 		response,
-		m.cache,
+		m.services,
 		thenStack,
 		route,
 	)
 
 	ctx.Next()
 	ctx.Response().Send()
+
+	concretes.RunAfterResponse(ctx)
 }
 
 //SetCachable sets cache provider.
 func (m *mango) SetCachable(cache contracts.Cachable) {
-	m.cache = cache
+	m.services.Cache = cache
+}
+
+//SetKeyring sets keyring used by signed and encrypted cookies.
+func (m *mango) SetKeyring(keyring contracts.Keyring) {
+	m.services.Keyring = keyring
+}
+
+//SetMode sets mode of application, see contracts.DevelopmentMode.
+func (m *mango) SetMode(mode string) {
+	m.services.Mode = mode
+}
+
+//SetViewEngine sets engine used to render ctx.View.
+func (m *mango) SetViewEngine(views contracts.ViewEngine) {
+	m.services.Views = views
+}
+
+//SetJSON configures how requests and responses are encoded as JSON.
+func (m *mango) SetJSON(opt contracts.JSONOption) {
+	m.services.JSON = &opt
+}
+
+//TrustProxies sets sources allowed to pass their own X-Request-ID,
+//sources are given as CIDR notations or plain IPs.
+func (m *mango) TrustProxies(sources ...string) {
+	trusted, err := concretes.ParseTrusted(sources...)
+	if err != nil {
+		logy.Std().Warn("TrustProxies:", err.Error())
+		return
+	}
+
+	m.trusted = trusted
 }
 
 //Use appends contracts.ThenableFunc to built-in stack.
@@ -54,33 +96,38 @@ func (m *mango) Use(next contracts.ThenableFunc) {
 }
 
 //SetDefaultRoute set customized not found error handler.
-func (m *mango) SetDefaultRoute(fn contracts.Callable) {
+func (m *mango) SetDefaultRoute(fn contracts.Handler) {
 	m.router.SetDefaultRoute(fn)
 }
 
 //Get register a GET route.
-func (m *mango) Get(path string, fn contracts.Callable, thenStack ...contracts.ThenableFunc) {
-	m.router.Get(path, fn, thenStack...)
+func (m *mango) Get(path string, fn contracts.Handler, thenStack ...contracts.ThenableFunc) contracts.Route {
+	return m.router.Get(path, fn, thenStack...)
 }
 
 //Post register a POST route.
-func (m *mango) Post(path string, fn contracts.Callable, thenStack ...contracts.ThenableFunc) {
-	m.router.Post(path, fn, thenStack...)
+func (m *mango) Post(path string, fn contracts.Handler, thenStack ...contracts.ThenableFunc) contracts.Route {
+	return m.router.Post(path, fn, thenStack...)
 }
 
 //Put register a PUT route.
-func (m *mango) Put(path string, fn contracts.Callable, thenStack ...contracts.ThenableFunc) {
-	m.router.Put(path, fn, thenStack...)
+func (m *mango) Put(path string, fn contracts.Handler, thenStack ...contracts.ThenableFunc) contracts.Route {
+	return m.router.Put(path, fn, thenStack...)
 }
 
 //Delete register a DELETE route.
-func (m *mango) Delete(path string, fn contracts.Callable, thenStack ...contracts.ThenableFunc) {
-	m.router.Delete(path, fn, thenStack...)
+func (m *mango) Delete(path string, fn contracts.Handler, thenStack ...contracts.ThenableFunc) contracts.Route {
+	return m.router.Delete(path, fn, thenStack...)
 }
 
 //Any register a route without request type limit.
-func (m *mango) Any(path string, fn contracts.Callable, thenStack ...contracts.ThenableFunc) {
-	m.router.Any(path, fn, thenStack...)
+func (m *mango) Any(path string, fn contracts.Handler, thenStack ...contracts.ThenableFunc) contracts.Route {
+	return m.router.Any(path, fn, thenStack...)
+}
+
+//WebSocket register a WebSocket route, middlewares run before the upgrade.
+func (m *mango) WebSocket(path string, fn contracts.WebSocketHandler, thenStack ...contracts.ThenableFunc) contracts.Route {
+	return m.router.WebSocket(path, fn, thenStack...)
 }
 
 //Group create route group with dedicated prefix path.
@@ -89,12 +136,15 @@ func (m *mango) Group(path string, fn func(contracts.Router), thenStack ...contr
 }
 
 func (m *mango) start(addr string, fn func(*http.Server)) {
-	shouldStop := make(chan os.Signal)
+	shouldStop := make(chan os.Signal, 1)
 	signal.Notify(shouldStop, os.Interrupt, os.Kill)
 
 	server := &http.Server{
 		Addr:    addr,
 		Handler: m,
+		BaseContext: func(net.Listener) context.Context {
+			return m.base
+		},
 	}
 
 	m.emit("starting")
@@ -109,6 +159,8 @@ func (m *mango) start(addr string, fn func(*http.Server)) {
 	<-shouldStop
 	logy.Std().Warn("Server is shutting down...")
 
+	m.cancel() //notifies running handlers through ctx.Std().
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -118,6 +170,7 @@ func (m *mango) start(addr string, fn func(*http.Server)) {
 	}
 
 	logy.Std().Info("Server is stopping daemon tasks...")
+	m.services.Workers.Close() //waits for after response hooks.
 	m.emit("shutdown")
 
 	logy.Std().Info("Server stopped gracefully.")
@@ -127,7 +180,7 @@ func (m *mango) start(addr string, fn func(*http.Server)) {
 func (m *mango) Start(addr string) {
 	m.start(addr, func(s *http.Server) {
 		err := s.ListenAndServe()
-		if err != nil {
+		if err != nil && err != http.ErrServerClosed {
 			logy.Std().Error(err.Error())
 		}
 	})
@@ -137,7 +190,7 @@ func (m *mango) Start(addr string) {
 func (m *mango) StartTLS(addr, certFile, keyFile string) {
 	m.start(addr, func(s *http.Server) {
 		err := s.ListenAndServeTLS(certFile, keyFile)
-		if err != nil {
+		if err != nil && err != http.ErrServerClosed {
 			logy.Std().Error(err.Error())
 		}
 	})
@@ -156,7 +209,7 @@ func (m *mango) StartAutoTLS(addr string, caStore autocert.Cache, domains ...str
 		s.TLSConfig = &tls.Config{GetCertificate: c.GetCertificate}
 
 		err := s.ListenAndServeTLS("", "")
-		if err != nil {
+		if err != nil && err != http.ErrServerClosed {
 			logy.Std().Error(err.Error())
 		}
 	})
@@ -171,6 +224,17 @@ func (m *mango) On(event string, fn func()) {
 	m.events[event] = append(m.events[event], fn)
 }
 
+// OnError sets handler that decides how request errors are logged and rendered.
+func (m *mango) OnError(fn contracts.ErrorHandler) {
+	m.services.OnError = fn
+}
+
+// SetErrorRenderer sets renderer of error pages shown to browsers for
+// status code, code 0 sets the fallback of all codes.
+func (m *mango) SetErrorRenderer(code int, fn contracts.ErrorRenderer) {
+	m.services.ErrorRenderers[code] = fn
+}
+
 func (m *mango) emit(event string) {
 	if fns, ok := m.events[event]; ok {
 		for _, fn := range fns {
@@ -181,15 +245,35 @@ func (m *mango) emit(event string) {
 
 // New returns an new Mango instance
 func New() contracts.Mango {
+	base, cancel := context.WithCancel(context.Background())
+
+	mode := os.Getenv("MANGO_ENV")
+	if mode == "" {
+		mode = contracts.ProductionMode
+	}
+
+	router := concretes.NewRouter()
+
 	m := &mango{
-		concretes.NewRouter(),
+		router,
 		[]contracts.ThenableFunc{},
-		concretes.NewMemoryCache(15 * time.Minute),
+		&concretes.Services{
+			Cache:          concretes.NewMemoryCache(15 * time.Minute),
+			Keyring:        concretes.NewKeyring(),
+			OnError:        concretes.DefaultErrorHandler,
+			Mode:           mode,
+			Router:         router,
+			Workers:        concretes.NewWorkerPool(runtime.NumCPU(), 1024),
+			ErrorRenderers: map[int]contracts.ErrorRenderer{},
+		},
 		map[string][]func(){},
+		[]*net.IPNet{},
+		base,
+		cancel,
 	}
 
 	m.SetDefaultRoute(func(ctx contracts.Context) (int, interface{}) {
-		return 404, "page not found"
+		return 0, &contracts.HTTPError{Code: http.StatusNotFound, Message: "page not found"}
 	})
 
 	m.thenStack = []contracts.ThenableFunc{}
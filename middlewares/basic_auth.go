@@ -25,7 +25,7 @@ func BasicAuth(credentials map[string]string) contracts.ThenableFunc {
 
 				raw, err := base64.StdEncoding.DecodeString(token)
 				if err != nil {
-					ctx.Response().SetStatus(http.StatusInternalServerError)
+					ctx.AbortWithStatus(http.StatusInternalServerError)
 					return
 				}
 
@@ -40,7 +40,7 @@ func BasicAuth(credentials map[string]string) contracts.ThenableFunc {
 			}
 		}
 
-		ctx.Response().SetStatus(http.StatusUnauthorized)
 		ctx.Response().Header().Set("WWW-Authenticate", "Basic realm=\"Restricted\"")
+		ctx.AbortWithStatus(http.StatusUnauthorized)
 	}
 }
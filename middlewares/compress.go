@@ -2,17 +2,28 @@ package middlewares
 
 import (
 	"compress/gzip"
+	"net/http"
 	"strings"
 
 	"github.com/go-mango/mango/contracts"
 )
 
-//Compress compress response data.
+//Compress compress response data. Streamed responses such as files,
+//readers and event streams have been sent already and are left as is.
 func Compress() contracts.ThenableFunc {
 	return func(ctx contracts.ThenableContext) {
 		ctx.Next() //continues to execute middlewares.
 
-		if ctx.Response().Size() == 0 {
+		if ctx.Response().IsStreaming() || ctx.Response().Size() == 0 {
+			return
+		}
+
+		//partial content can not be re-encoded, ranges address the original bytes.
+		if ctx.Response().Status() == http.StatusPartialContent {
+			return
+		}
+
+		if ctx.Response().Header().Get("Content-Encoding") != "" {
 			return
 		}
 
@@ -36,6 +47,9 @@ func Compress() contracts.ThenableFunc {
 				return
 			}
 
+			//length and ranges of the original body no longer apply.
+			ctx.Response().Header().Del("Content-Length")
+			ctx.Response().Header().Del("Accept-Ranges")
 			ctx.Response().Header().Set("Content-Encoding", "gzip")
 			ctx.Response().Header().Set("Vary", "Accept-Encoding")
 		}
@@ -0,0 +1,80 @@
+package middlewares_test
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-mango/mango"
+	"github.com/go-mango/mango/concretes"
+	"github.com/go-mango/mango/contracts"
+	"github.com/go-mango/mango/middlewares"
+)
+
+func TestCompress(t *testing.T) {
+	body := strings.Repeat("hello ", 100)
+
+	m := mango.New()
+	m.Use(middlewares.Compress())
+
+	m.Get("/text", func(ctx contracts.Context) (int, interface{}) {
+		return 200, body
+	})
+
+	m.Get("/file", func(ctx contracts.Context) (int, interface{}) {
+		return 0, concretes.ServeContent(ctx, "a.txt", time.Time{}, strings.NewReader(body))
+	})
+
+	m.Get("/events", func(ctx contracts.Context) (int, interface{}) {
+		return ctx.SSE(func(s contracts.EventStream) error {
+			return s.Send(contracts.Event{Data: "x"})
+		})
+	})
+
+	for _, tt := range []struct {
+		path, accept, rng string
+		code              int
+		gzipped           bool
+	}{
+		{"/text", "gzip", "", 200, true},
+		{"/text", "", "", 200, false},
+		{"/file", "gzip", "", 200, false},
+		{"/file", "gzip", "bytes=0-4", 206, false},
+		{"/events", "gzip", "", 200, false},
+	} {
+		r := httptest.NewRequest("GET", tt.path, nil)
+		r.Header.Set("Accept-Encoding", tt.accept)
+		if tt.rng != "" {
+			r.Header.Set("Range", tt.rng)
+		}
+
+		w := httptest.NewRecorder()
+		m.ServeHTTP(w, r)
+
+		gzipped := w.Header().Get("Content-Encoding") == "gzip"
+		if w.Code != tt.code || gzipped != tt.gzipped {
+			t.Errorf("%s %q: %d gzip=%v, want %d gzip=%v", tt.path, tt.rng, w.Code, gzipped, tt.code, tt.gzipped)
+			continue
+		}
+
+		if !gzipped {
+			continue
+		}
+
+		if w.Header().Get("Content-Length") != "" {
+			t.Errorf("%s: Content-Length %s sent with gzipped body", tt.path, w.Header().Get("Content-Length"))
+		}
+
+		zr, err := gzip.NewReader(w.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if b, err := ioutil.ReadAll(zr); err != nil || string(b) != body {
+			t.Errorf("%s: gunzipped %.10q, %v", tt.path, b, err)
+		}
+	}
+}
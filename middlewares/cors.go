@@ -21,7 +21,7 @@ func Cors(opt CorsOption) contracts.ThenableFunc {
 		ctx.Response().Header().Add("Access-Control-Allow-Headers", opt.Headers)
 
 		if ctx.Request().Method() == "OPTIONS" {
-			ctx.Response().SetStatus(http.StatusOK)
+			ctx.AbortWithStatus(http.StatusOK)
 		} else {
 			ctx.Next()
 		}
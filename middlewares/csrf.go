@@ -0,0 +1,94 @@
+package middlewares
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"io"
+	"net/http"
+
+	"github.com/go-mango/mango/concretes"
+	"github.com/go-mango/mango/contracts"
+)
+
+//CSRFOption configures CSRF middleware.
+type CSRFOption struct {
+	CookieName string
+	HeaderName string
+	Path       string
+	Domain     string
+	Secure     bool
+	SameSite   http.SameSite
+}
+
+//CSRF protects unsafe requests with double submit tokens, the token
+//is kept in a signed cookie and must be echoed back by the form field
+//"csrf_token" or the X-CSRF-Token header. Views render it with the
+//csrf_field and csrf_token helpers.
+func CSRF(opt CSRFOption) contracts.ThenableFunc {
+	if opt.CookieName == "" {
+		opt.CookieName = "mango_csrf"
+	}
+
+	if opt.HeaderName == "" {
+		opt.HeaderName = "X-CSRF-Token"
+	}
+
+	if opt.Path == "" {
+		opt.Path = "/"
+	}
+
+	if opt.SameSite == 0 {
+		opt.SameSite = http.SameSiteLaxMode
+	}
+
+	return func(ctx contracts.ThenableContext) {
+		token, err := ctx.SignedCookie(opt.CookieName)
+		if err != nil || token == "" {
+			token, err = newCSRFToken()
+			if err != nil {
+				ctx.AbortWith(0, err)
+				return
+			}
+
+			ctx.SetSignedCookie(&http.Cookie{
+				Name:     opt.CookieName,
+				Value:    token,
+				Path:     opt.Path,
+				Domain:   opt.Domain,
+				Secure:   opt.Secure || ctx.Request().IsTLS(),
+				HttpOnly: true,
+				SameSite: opt.SameSite,
+			})
+		}
+
+		ctx.Set(concretes.CSRFTokenKey, token)
+
+		switch ctx.Request().Method() {
+		case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+			ctx.Next()
+			return
+		}
+
+		sent := ctx.Request().Header().Get(opt.HeaderName)
+		if sent == "" {
+			sent = ctx.Request().Parent().PostFormValue(concretes.CSRFTokenKey)
+		}
+
+		if subtle.ConstantTimeCompare([]byte(sent), []byte(token)) != 1 {
+			ctx.AbortWith(0, &contracts.HTTPError{Code: http.StatusForbidden, Message: "invalid CSRF token"})
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+func newCSRFToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,76 @@
+package middlewares
+
+import (
+	"mime"
+	"strings"
+
+	"github.com/go-mango/mango/concretes"
+	"github.com/go-mango/mango/contracts"
+)
+
+//skipEnvelopeKey marks requests of routes opted out of Envelope.
+const skipEnvelopeKey = "mango.envelope.skip"
+
+//EnvelopeOption configures Envelope middleware.
+type EnvelopeOption struct {
+	//Meta adds fields to meta of successful responses,
+	//request_id is always included.
+	Meta func(ctx contracts.Context, code int, v interface{}) map[string]interface{}
+}
+
+//Envelope wraps successful JSON responses in {"data": ..., "meta": {...}}
+//and errors in {"error": {...}}. Use it on groups to shape a whole API,
+//routes opt out with SkipEnvelope.
+//
+/*
+	m.Group("/api", func(r contracts.Router) {
+		r.Get("/users", users)
+		r.Get("/health", health, middlewares.SkipEnvelope())
+	}, middlewares.Envelope(middlewares.EnvelopeOption{}))
+*/
+func Envelope(opt EnvelopeOption) contracts.ThenableFunc {
+	return func(ctx contracts.ThenableContext) {
+		ctx.Transform(func(ctx contracts.Context, code int, v interface{}) (int, interface{}, error) {
+			if ctx.GetBool(skipEnvelopeKey) {
+				return code, v, nil
+			}
+
+			mediaType, _, _ := mime.ParseMediaType(ctx.Response().Header().Get("Content-Type"))
+			if !strings.HasSuffix(mediaType, "json") {
+				return code, v, nil
+			}
+
+			if code >= 400 {
+				ctx.Response().Header().Set("Content-Type", "application/json; charset=utf-8")
+				return code, &concretes.Envelope{Error: v}, nil
+			}
+
+			meta := map[string]interface{}{"request_id": ctx.RequestID()}
+			if opt.Meta != nil {
+				for k, value := range opt.Meta(ctx, code, v) {
+					meta[k] = value
+				}
+			}
+
+			if e, ok := v.(*concretes.Envelope); ok {
+				if e.Meta == nil {
+					e.Meta = meta
+				}
+
+				return code, e, nil
+			}
+
+			return code, &concretes.Envelope{Data: v, Meta: meta}, nil
+		})
+
+		ctx.Next()
+	}
+}
+
+//SkipEnvelope opts a route out of Envelope applied to its group.
+func SkipEnvelope() contracts.ThenableFunc {
+	return func(ctx contracts.ThenableContext) {
+		ctx.Set(skipEnvelopeKey, true)
+		ctx.Next()
+	}
+}
@@ -0,0 +1,51 @@
+package middlewares
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"hash"
+
+	"github.com/go-mango/mango/concretes"
+	"github.com/go-mango/mango/contracts"
+)
+
+//ETagOption configures ETag middleware.
+type ETagOption struct {
+	Weak bool
+	Hash func() hash.Hash
+}
+
+//ETag tags successful buffered responses with a hash of their body and
+//answers If-None-Match and If-Modified-Since with 304. ETag and
+//Last-Modified set by handlers are kept and evaluated as well.
+//Register it before Compress so that the compressed body is tagged.
+func ETag(opt ETagOption) contracts.ThenableFunc {
+	if opt.Hash == nil {
+		opt.Hash = sha1.New
+	}
+
+	return func(ctx contracts.ThenableContext) {
+		ctx.Next()
+
+		res := ctx.Response()
+		if res.IsStreaming() || res.Status() < 200 || res.Status() >= 300 {
+			return
+		}
+
+		if res.Header().Get("ETag") == "" && res.Size() > 0 {
+			h := opt.Hash()
+			h.Write(res.Buffered())
+
+			tag := "\"" + hex.EncodeToString(h.Sum(nil)) + "\""
+			if opt.Weak {
+				tag = "W/" + tag
+			}
+
+			res.Header().Set("ETag", tag)
+		}
+
+		if concretes.NotModified(ctx) {
+			concretes.WriteNotModified(ctx)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package middlewares_test
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-mango/mango"
+	"github.com/go-mango/mango/contracts"
+	"github.com/go-mango/mango/middlewares"
+)
+
+func etagServer(opt middlewares.ETagOption) http.Handler {
+	m := mango.New()
+	m.Use(middlewares.ETag(opt))
+
+	m.Get("/body", func(ctx contracts.Context) (int, interface{}) {
+		return 200, "hello"
+	})
+
+	m.Get("/tagged", func(ctx contracts.Context) (int, interface{}) {
+		ctx.Response().Header().Set("ETag", `"own"`)
+		return 200, "hello"
+	})
+
+	m.Get("/missing", func(ctx contracts.Context) (int, interface{}) {
+		return 404, "nope"
+	})
+
+	m.Get("/stream", func(ctx contracts.Context) (int, interface{}) {
+		return 200, strings.NewReader("hello") //io.Reader responses are streamed.
+	})
+
+	m.Post("/body", func(ctx contracts.Context) (int, interface{}) {
+		return 200, "hello"
+	})
+
+	return m
+}
+
+func TestETag(t *testing.T) {
+	sum := sha1.Sum([]byte("hello"))
+	tag := `"` + hex.EncodeToString(sum[:]) + `"`
+
+	for _, tt := range []struct {
+		weak        bool
+		method      string
+		path        string
+		ifNoneMatch string
+		code        int
+		etag        string
+	}{
+		{false, "GET", "/body", "", 200, tag},
+		{true, "GET", "/body", "", 200, "W/" + tag},
+		{false, "GET", "/body", tag, 304, tag},
+		{false, "GET", "/body", "W/" + tag, 304, tag},
+		{true, "GET", "/body", tag, 304, "W/" + tag},
+		{false, "GET", "/body", `"other", ` + tag, 304, tag},
+		{false, "GET", "/body", `"other"`, 200, tag},
+		{false, "GET", "/body", "*", 304, tag},
+		{false, "POST", "/body", tag, 200, tag},
+		{false, "GET", "/tagged", "", 200, `"own"`},
+		{false, "GET", "/tagged", `"own"`, 304, `"own"`},
+		{false, "GET", "/missing", "", 404, ""},
+		{false, "GET", "/missing", "*", 404, ""},
+		{false, "GET", "/stream", "", 200, ""},
+		{false, "GET", "/stream", "*", 200, ""},
+	} {
+		r := httptest.NewRequest(tt.method, tt.path, nil)
+		if tt.ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", tt.ifNoneMatch)
+		}
+
+		w := httptest.NewRecorder()
+		etagServer(middlewares.ETagOption{Weak: tt.weak}).ServeHTTP(w, r)
+
+		if w.Code != tt.code || w.Header().Get("ETag") != tt.etag {
+			t.Errorf("weak=%v %s %s If-None-Match %q = %d %q, want %d %q",
+				tt.weak, tt.method, tt.path, tt.ifNoneMatch, w.Code, w.Header().Get("ETag"), tt.code, tt.etag)
+		}
+
+		if w.Code == 304 && (w.Body.Len() > 0 || w.Header().Get("Content-Type") != "") {
+			t.Errorf("%s %s: 304 carries body %q or Content-Type %q", tt.method, tt.path, w.Body.String(), w.Header().Get("Content-Type"))
+		}
+	}
+}
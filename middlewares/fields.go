@@ -0,0 +1,257 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-mango/mango/concretes"
+	"github.com/go-mango/mango/contracts"
+)
+
+//FieldsOption configures Fields middleware.
+type FieldsOption struct {
+	//Param and ExcludeParam are names of query parameters,
+	//they default to "fields" and "exclude".
+	Param        string
+	ExcludeParam string
+	//Strict rejects unknown fields with 400.
+	Strict bool
+}
+
+//Fields trims JSON responses to fields asked by client, nested fields
+//are separated by dots and names follow json struct tags.
+//
+/*
+	GET /users?fields=id,name,owner.email
+	GET /users?exclude=owner.address
+*/
+func Fields(opt FieldsOption) contracts.ThenableFunc {
+	if opt.Param == "" {
+		opt.Param = "fields"
+	}
+
+	if opt.ExcludeParam == "" {
+		opt.ExcludeParam = "exclude"
+	}
+
+	return func(ctx contracts.ThenableContext) {
+		query := ctx.Request().URL().Query()
+		include := parseFields(query.Get(opt.Param))
+		exclude := parseFields(query.Get(opt.ExcludeParam))
+
+		if include == nil && exclude == nil {
+			ctx.Next()
+			return
+		}
+
+		ctx.Transform(func(ctx contracts.Context, code int, v interface{}) (int, interface{}, error) {
+			mediaType, _, _ := mime.ParseMediaType(ctx.Response().Header().Get("Content-Type"))
+			if !strings.HasSuffix(mediaType, "json") || v == nil || code >= 400 {
+				return code, v, nil
+			}
+
+			//envelopes keep their shape, fields apply to the data they carry.
+			if e, ok := v.(*concretes.Envelope); ok {
+				if e.Data == nil {
+					return code, v, nil
+				}
+
+				data, err := trimFields(e.Data, include, exclude, opt.Strict)
+				if err != nil {
+					return code, nil, err
+				}
+
+				trimmed := *e
+				trimmed.Data = data
+
+				return code, &trimmed, nil
+			}
+
+			v, err := trimFields(v, include, exclude, opt.Strict)
+
+			return code, v, err
+		})
+
+		ctx.Next()
+	}
+}
+
+//trimFields selects and excludes fields of v, strict mode checks
+//them against type of v first.
+func trimFields(v interface{}, include, exclude fieldTree, strict bool) (interface{}, error) {
+	if strict {
+		unknown := []string{}
+		unknownFields(reflect.TypeOf(v), include, "", &unknown)
+		unknownFields(reflect.TypeOf(v), exclude, "", &unknown)
+
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			return nil, &contracts.HTTPError{Code: http.StatusBadRequest, Message: "unknown fields", Details: unknown}
+		}
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	if include != nil {
+		generic = selectFields(generic, include)
+	}
+
+	if exclude != nil {
+		generic = excludeFields(generic, exclude)
+	}
+
+	return generic, nil
+}
+
+//fieldTree is parsed field list, leaves are empty trees.
+type fieldTree map[string]fieldTree
+
+func parseFields(s string) fieldTree {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+
+	tree := fieldTree{}
+
+	for _, path := range strings.Split(s, ",") {
+		node := tree
+		for _, name := range strings.Split(strings.TrimSpace(path), ".") {
+			if name == "" {
+				continue
+			}
+
+			if node[name] == nil {
+				node[name] = fieldTree{}
+			}
+
+			node = node[name]
+		}
+	}
+
+	return tree
+}
+
+func selectFields(v interface{}, tree fieldTree) interface{} {
+	switch v := v.(type) {
+	case []interface{}:
+		for i := range v {
+			v[i] = selectFields(v[i], tree)
+		}
+	case map[string]interface{}:
+		for k := range v {
+			sub, ok := tree[k]
+			if !ok {
+				delete(v, k)
+			} else if len(sub) > 0 {
+				v[k] = selectFields(v[k], sub)
+			}
+		}
+	}
+
+	return v
+}
+
+func excludeFields(v interface{}, tree fieldTree) interface{} {
+	switch v := v.(type) {
+	case []interface{}:
+		for i := range v {
+			v[i] = excludeFields(v[i], tree)
+		}
+	case map[string]interface{}:
+		for k, sub := range tree {
+			if len(sub) == 0 {
+				delete(v, k)
+			} else if _, ok := v[k]; ok {
+				v[k] = excludeFields(v[k], sub)
+			}
+		}
+	}
+
+	return v
+}
+
+//unknownFields collects paths of tree that type t can not have.
+func unknownFields(t reflect.Type, tree fieldTree, prefix string, unknown *[]string) {
+	if t == nil {
+		return
+	}
+
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+
+	for name, sub := range tree {
+		var (
+			ft    reflect.Type
+			found bool
+		)
+
+		switch t.Kind() {
+		case reflect.Interface:
+			continue
+		case reflect.Map:
+			ft, found = t.Elem(), true
+		case reflect.Struct:
+			ft, found = jsonField(t, name)
+		}
+
+		if !found {
+			*unknown = append(*unknown, prefix+name)
+			continue
+		}
+
+		unknownFields(ft, sub, prefix+name+".", unknown)
+	}
+}
+
+//jsonField finds type of field encoded under name by encoding/json.
+func jsonField(t reflect.Type, name string) (reflect.Type, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag := strings.Split(f.Tag.Get("json"), ",")[0]
+		if tag == "-" {
+			continue
+		}
+
+		if f.Anonymous && tag == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+
+			if ft.Kind() == reflect.Struct {
+				if found, ok := jsonField(ft, name); ok {
+					return found, true
+				}
+
+				continue
+			}
+		}
+
+		if f.PkgPath != "" {
+			continue
+		}
+
+		if tag == name || (tag == "" && f.Name == name) {
+			return f.Type, true
+		}
+	}
+
+	return nil, false
+}
@@ -14,14 +14,21 @@ func Record() contracts.ThenableFunc {
 		ctx.Next()
 		dur := time.Since(st).String()
 
+		state := ""
+		if ctx.IsAborted() {
+			state = "\taborted"
+		}
+
 		logy.Std().Infof(
-			"%d %s\t%dB\t%s\t%s\t%s",
+			"%s\t%d %s\t%dB\t%s\t%s\t%s%s",
+			ctx.RequestID(),
 			ctx.Response().Status(),
 			dur,
 			ctx.Response().Size(),
 			ctx.Request().IP(),
 			ctx.Request().Method(),
 			ctx.Request().URI(),
+			state,
 		)
 	}
 }
@@ -1,32 +1,29 @@
 package middlewares
 
 import (
-	"fmt"
 	"net/http"
 	"runtime/debug"
 
-	"github.com/go-mango/logy"
+	"github.com/go-mango/mango/concretes"
 	"github.com/go-mango/mango/contracts"
 )
 
-//Recovery record every panic.
+//Recovery turns every panic into a 500 error, it is logged and
+//rendered by the application error handler with its stack trace.
 func Recovery() contracts.ThenableFunc {
 	return func(ctx contracts.ThenableContext) {
 		defer func() {
 			if v := recover(); v != nil {
-				var err error
-				switch v := v.(type) {
-				case error:
-					err = v
-				default:
-					err = fmt.Errorf("%v", v)
+				if v == http.ErrAbortHandler {
+					panic(v) //the server aborts the response silently.
 				}
 
-				logy.Std().Warnf("RECOVERED:", err.Error())
-
-				debug.PrintStack()
-
-				ctx.Response().SetStatus(http.StatusInternalServerError)
+				ctx.Abort()
+				ctx.Error(&contracts.HTTPError{
+					Code:    http.StatusInternalServerError,
+					Message: http.StatusText(http.StatusInternalServerError),
+					Err:     &concretes.PanicError{Value: v, Stack: debug.Stack()},
+				})
 			}
 		}()
 
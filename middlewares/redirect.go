@@ -21,6 +21,7 @@ func Redirect(opt RedirectOption) contracts.ThenableFunc {
 			to.Host = opt.MustHOST
 
 			ctx.Response().Redirect(http.StatusPermanentRedirect, to.String())
+			ctx.Abort()
 			return
 		}
 
@@ -30,6 +31,7 @@ func Redirect(opt RedirectOption) contracts.ThenableFunc {
 			to.Host = ctx.Request().Host()
 
 			ctx.Response().Redirect(http.StatusPermanentRedirect, to.String())
+			ctx.Abort()
 			return
 		}
 
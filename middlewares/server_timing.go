@@ -0,0 +1,57 @@
+package middlewares
+
+import (
+	"time"
+
+	"github.com/go-mango/mango/concretes"
+	"github.com/go-mango/mango/contracts"
+)
+
+//ServerTimingOption configures ServerTiming middleware.
+type ServerTimingOption struct {
+	//Trusted lists CIDR notations or plain IPs of clients allowed to
+	//see timings, nobody is when neither Trusted nor Allow is set.
+	//Behind a reverse proxy every request comes from the proxy address,
+	//trusting it exposes timings to all clients, use Allow instead.
+	Trusted []string
+	//Allow replaces Trusted when it is set, e.g. to check a header the
+	//proxy sets for authenticated staff and strips from client requests.
+	Allow func(contracts.Request) bool
+}
+
+//ServerTiming times every following middleware and the handler, and
+//sends them with metrics recorded by ctx.Timing in Server-Timing
+//header, which browser devtools display. Register it first so that
+//the whole chain is timed. Timings reveal internals, so the middleware
+//does nothing unless Trusted or Allow is set.
+func ServerTiming(opt ServerTimingOption) contracts.ThenableFunc {
+	if len(opt.Trusted) == 0 && opt.Allow == nil {
+		return func(ctx contracts.ThenableContext) {
+			ctx.Next()
+		}
+	}
+
+	trusted, err := concretes.ParseTrusted(opt.Trusted...)
+	if err != nil {
+		panic("invalid trusted source of ServerTiming: " + err.Error())
+	}
+
+	if opt.Allow == nil {
+		opt.Allow = func(r contracts.Request) bool {
+			return concretes.IsTrusted(r, trusted)
+		}
+	}
+
+	return func(ctx contracts.ThenableContext) {
+		if !opt.Allow(ctx.Request()) {
+			ctx.Next()
+			return
+		}
+
+		concretes.EnableTiming(ctx)
+
+		start := time.Now()
+		ctx.Next()
+		ctx.Timing("total", time.Since(start))
+	}
+}
@@ -0,0 +1,44 @@
+package middlewares_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-mango/mango"
+	"github.com/go-mango/mango/contracts"
+	"github.com/go-mango/mango/middlewares"
+)
+
+func TestServerTimingTrust(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		opt    middlewares.ServerTimingOption
+		remote string
+		sent   bool
+	}{
+		{"unset from loopback", middlewares.ServerTimingOption{}, "127.0.0.1:1234", false},
+		{"unset from anywhere", middlewares.ServerTimingOption{}, "203.0.113.9:1234", false},
+		{"trusted", middlewares.ServerTimingOption{Trusted: []string{"10.0.0.0/8"}}, "10.1.2.3:1234", true},
+		{"untrusted", middlewares.ServerTimingOption{Trusted: []string{"10.0.0.0/8"}}, "203.0.113.9:1234", false},
+		{"allowed", middlewares.ServerTimingOption{Allow: func(r contracts.Request) bool {
+			return r.Header().Get("X-Staff") == "1"
+		}}, "127.0.0.1:1234", true},
+	} {
+		m := mango.New()
+		m.Use(middlewares.ServerTiming(tt.opt))
+		m.Get("/", func(ctx contracts.Context) (int, interface{}) {
+			return 200, "ok"
+		})
+
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = tt.remote
+		r.Header.Set("X-Staff", "1")
+
+		w := httptest.NewRecorder()
+		m.ServeHTTP(w, r)
+
+		if sent := w.Header().Get("Server-Timing") != ""; sent != tt.sent {
+			t.Errorf("%s: Server-Timing sent = %v, want %v", tt.name, sent, tt.sent)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package middlewares
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-mango/logy"
+	"github.com/go-mango/mango/concretes"
+	"github.com/go-mango/mango/contracts"
+)
+
+//SessionOption configures Session middleware.
+type SessionOption struct {
+	Store       contracts.SessionStore
+	CookieName  string
+	Path        string
+	Domain      string
+	Secure      bool
+	SameSite    http.SameSite
+	IdleTimeout time.Duration
+	Lifetime    time.Duration
+}
+
+//Session starts server side session for incoming requests,
+//the session ID is kept in a signed HttpOnly cookie.
+//
+/*
+	m.Use(middlewares.Session(middlewares.SessionOption{
+		Store: concretes.NewCacheSessionStore(redisCache, "session:"),
+	}))
+*/
+func Session(opt SessionOption) contracts.ThenableFunc {
+	if opt.Store == nil {
+		opt.Store = concretes.NewMemorySessionStore()
+	}
+
+	if opt.CookieName == "" {
+		opt.CookieName = "mango_session"
+	}
+
+	if opt.Path == "" {
+		opt.Path = "/"
+	}
+
+	if opt.SameSite == 0 {
+		opt.SameSite = http.SameSiteLaxMode
+	}
+
+	if opt.IdleTimeout == 0 {
+		opt.IdleTimeout = 30 * time.Minute
+	}
+
+	if opt.Lifetime == 0 {
+		opt.Lifetime = 24 * time.Hour
+	}
+
+	manager := &concretes.SessionManager{
+		Store:       opt.Store,
+		IdleTimeout: opt.IdleTimeout,
+		Lifetime:    opt.Lifetime,
+	}
+
+	return func(ctx contracts.ThenableContext) {
+		id, _ := ctx.SignedCookie(opt.CookieName)
+		session := manager.Load(ctx, id)
+		ctx.SetSession(session)
+
+		//cookie must go out with headers, streamed and hijacked
+		//responses commit them before handler returns.
+		ctx.Response().OnBeforeSend(func(res contracts.Response) {
+			if !manager.Changed(session) {
+				return
+			}
+
+			if err := manager.Save(ctx, session); err != nil {
+				logy.Std().Warnf("SESSION: [%s] %s", ctx.RequestID(), err.Error())
+				return
+			}
+
+			ctx.SetSignedCookie(&http.Cookie{
+				Name:     opt.CookieName,
+				Value:    session.ID(),
+				Path:     opt.Path,
+				Domain:   opt.Domain,
+				Expires:  manager.Expires(session),
+				Secure:   opt.Secure || ctx.Request().IsTLS(),
+				HttpOnly: true,
+				SameSite: opt.SameSite,
+			})
+		})
+
+		ctx.Next()
+
+		//changes made after headers were sent are still persisted.
+		if ctx.Response().Committed() && manager.Changed(session) {
+			if err := manager.Save(ctx, session); err != nil {
+				logy.Std().Warnf("SESSION: [%s] %s", ctx.RequestID(), err.Error())
+			}
+		}
+	}
+}
@@ -6,16 +6,10 @@ import (
 	"strings"
 
 	"github.com/go-mango/logy"
+	"github.com/go-mango/mango/concretes"
 	"github.com/go-mango/mango/contracts"
 
 	"os"
-
-	"time"
-
-	"mime"
-	"path/filepath"
-
-	"io"
 )
 
 //StaticOption configuration of Static middleware.
@@ -68,20 +62,7 @@ func Static(opt StaticOption) contracts.ThenableFunc {
 				return
 			}
 
-			if !stat.ModTime().IsZero() && !stat.ModTime().Equal(time.Unix(0, 0)) {
-				ctx.Response().Header().Add("Last-Modified", stat.ModTime().UTC().Format(http.TimeFormat))
-			}
-
-			if _, ok := ctx.Response().Header()["Content-Type"]; !ok {
-				m := mime.TypeByExtension(filepath.Ext(stat.Name()))
-				if m == "" {
-					m = "application/octet-stream"
-				}
-
-				ctx.Response().Header().Add("Content-Type", m)
-			}
-
-			_, err = io.Copy(ctx.Response(), file)
+			err = concretes.ServeContent(ctx, stat.Name(), stat.ModTime(), file)
 			if err != nil {
 				ctx.Response().Clear()
 				ctx.Response().SetStatus(http.StatusInternalServerError)
@@ -49,7 +49,7 @@ func Throttle(qps int) contracts.ThenableFunc {
 			if time.Since(t.t) <= 1*time.Second {
 
 				if t.c >= qps {
-					ctx.Response().SetStatus(http.StatusTooManyRequests)
+					ctx.AbortWithStatus(http.StatusTooManyRequests)
 					return
 				}
 
@@ -0,0 +1,33 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-mango/mango/contracts"
+)
+
+//Timeout limits how long a route may take, the deadline is
+//passed to handlers through ctx.Std() so that database calls
+//and outgoing requests can be canceled as well. Late responses are
+//replaced by a 503 rendered by the application error handler.
+func Timeout(d time.Duration) contracts.ThenableFunc {
+	return func(ctx contracts.ThenableContext) {
+		std, cancel := context.WithTimeout(ctx.Std(), d)
+		defer cancel()
+
+		ctx.SetStd(std)
+		ctx.Next()
+
+		if std.Err() == context.DeadlineExceeded {
+			ctx.Response().Clear()
+			ctx.Response().Header().Del("Content-Type") //of the abandoned body.
+			ctx.Error(&contracts.HTTPError{
+				Code:    http.StatusServiceUnavailable,
+				Message: "request timed out",
+				Err:     std.Err(),
+			})
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package middlewares_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-mango/mango"
+	"github.com/go-mango/mango/contracts"
+	"github.com/go-mango/mango/middlewares"
+)
+
+func TestTimeout(t *testing.T) {
+	m := mango.New()
+	m.Use(middlewares.Timeout(10 * time.Millisecond))
+
+	m.Get("/slow", func(ctx contracts.Context) (int, interface{}) {
+		<-ctx.Std().Done()
+		return 200, map[string]string{"late": "yes"}
+	})
+
+	m.Get("/fast", func(ctx contracts.Context) (int, interface{}) {
+		return 200, map[string]string{"late": "no"}
+	})
+
+	for _, tt := range []struct {
+		path, accept string
+		code         int
+		contentType  string
+	}{
+		{"/slow", "application/json", 503, "application/problem+json"},
+		{"/slow", "text/html", 503, "text/html; charset=utf-8"},
+		{"/fast", "application/json", 200, "application/json; charset=utf-8"},
+	} {
+		r := httptest.NewRequest("GET", tt.path, nil)
+		r.Header.Set("Accept", tt.accept)
+
+		w := httptest.NewRecorder()
+		m.ServeHTTP(w, r)
+
+		if w.Code != tt.code || w.Header().Get("Content-Type") != tt.contentType {
+			t.Errorf("%s %s = %d %q, want %d %q", tt.path, tt.accept, w.Code, w.Header().Get("Content-Type"), tt.code, tt.contentType)
+		}
+
+		if tt.code == 503 && (w.Body.Len() == 0 || strings.Contains(w.Body.String(), "late")) {
+			t.Errorf("%s %s: body %q", tt.path, tt.accept, w.Body.String())
+		}
+	}
+}